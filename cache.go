@@ -0,0 +1,257 @@
+package lynx
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache configures caching of GET responses, for clients polling the same resources.
+// Only reads of resources such as functions, devices and installations are cached, never
+// ping, status, logs, traces or file downloads.
+// Responses with an ETag or Last-Modified header are revalidated with the server on every
+// use, which answers 304 Not Modified if they are unchanged. Other responses are reused
+// until TTL expires. Create, update and delete requests through the client invalidate the
+// cached responses of the same resource type, for example all functions after UpdateFunction.
+type Cache struct {
+	// TTL is how long responses without ETag or Last-Modified are used, defaults to 10s
+	TTL time.Duration
+	// MaxEntries is the number of responses kept, the least recently used are evicted. Defaults to 1000
+	MaxEntries int
+}
+
+// maxCachedBody is the largest response body that is cached
+const maxCachedBody = 1 << 20
+
+type cacheEntry struct {
+	key          string
+	resource     string
+	header       http.Header
+	body         []byte
+	etag         string
+	lastModified string
+	expires      time.Time
+}
+
+type responseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+func newResponseCache(conf *Cache) *responseCache {
+	if conf == nil {
+		return nil
+	}
+	c := &responseCache{
+		ttl:        conf.TTL,
+		maxEntries: conf.MaxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+	if c.ttl <= 0 {
+		c.ttl = time.Second * 10
+	}
+	if c.maxEntries <= 0 {
+		c.maxEntries = 1000
+	}
+	return c
+}
+
+// cacheKey identifies a response by URL and the credentials it was requested with,
+// so that clients sharing a cache never see responses for another identity.
+func cacheKey(r *http.Request) string {
+	h := sha256.New()
+	for _, name := range []string{"Authorization", "X-API-Key"} {
+		h.Write([]byte(r.Header.Get(name)))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16]) + " " + r.URL.String()
+}
+
+// resourcePrefix returns the path up to the resource type, such as api/v2/functionx,
+// or an empty string if the path is not an API path.
+func resourcePrefix(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		if s == "api" && i+2 < len(segments) {
+			return strings.Join(segments[i:i+3], "/")
+		}
+	}
+	return ""
+}
+
+// cachedResources are the resource types whose GET responses are cached
+var cachedResources = map[string]bool{
+	"api/v2/functionx":        true,
+	"api/v2/devicex":          true,
+	"api/v2/installation":     true,
+	"api/v2/installationinfo": true,
+	"api/v2/organization":     true,
+	"api/v2/user":             true,
+	"api/v2/schedule":         true,
+	"api/v2/notification":     true,
+	"api/v2/file":             true,
+	"api/v2/edge":             true,
+}
+
+// cacheable reports whether GET responses for path are cached, which excludes downloads.
+func cacheable(path string) bool {
+	if !cachedResources[resourcePrefix(path)] {
+		return false
+	}
+	for _, s := range strings.Split(strings.Trim(path, "/"), "/") {
+		if s == "download" {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*cacheEntry), true
+}
+
+func (c *responseCache) put(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[entry.key]; ok {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// relatedResources are resource types that are read from another path than they are written to
+var relatedResources = map[string][]string{
+	"api/v2/installation": {"api/v2/installationinfo"},
+}
+
+// invalidate removes the responses of the resource type of path and its related resources,
+// or all responses if path is not an API path.
+func (c *responseCache) invalidate(path string) {
+	prefix := resourcePrefix(path)
+	resources := map[string]bool{prefix: true}
+	for _, related := range relatedResources[prefix] {
+		resources[related] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if prefix == "" || resources[e.Value.(*cacheEntry).resource] {
+			c.lru.Remove(e)
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// response returns a new response for the cached entry.
+func (e *cacheEntry) response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       r,
+	}
+}
+
+// roundTrip serves GET requests for cacheable paths from the cache, revalidating or refreshing entries with next.
+func (c *responseCache) roundTrip(r *http.Request, next RoundTripFunc) (*http.Response, error) {
+	if !cacheable(r.URL.Path) {
+		return next(r)
+	}
+	key := cacheKey(r)
+	entry, ok := c.get(key)
+	if ok && entry.etag == "" && entry.lastModified == "" && time.Now().Before(entry.expires) {
+		return entry.response(r), nil
+	}
+	if ok {
+		r = r.Clone(r.Context())
+		if entry.etag != "" {
+			r.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			r.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+	response, err := next(r)
+	if err != nil {
+		return nil, err
+	}
+	if ok && response.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+		response.Body.Close()
+		return entry.response(r), nil
+	}
+	if response.StatusCode != http.StatusOK || response.ContentLength > maxCachedBody ||
+		strings.Contains(response.Header.Get("Cache-Control"), "no-store") {
+		return response, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxCachedBody+1))
+	if err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBody {
+		response.Body = readCloser{io.MultiReader(bytes.NewReader(body), response.Body), response.Body}
+		return response, nil
+	}
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	c.put(&cacheEntry{
+		key:          key,
+		resource:     resourcePrefix(r.URL.Path),
+		header:       response.Header.Clone(),
+		body:         body,
+		etag:         response.Header.Get("ETag"),
+		lastModified: response.Header.Get("Last-Modified"),
+		expires:      time.Now().Add(c.ttl),
+	})
+	return response, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ClearCache removes all cached responses, see Options.Cache.
+func (c *Client) ClearCache() {
+	if c.cache != nil {
+		c.cache.clear()
+	}
+}
@@ -0,0 +1,140 @@
+package lynx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+)
+
+func TestClient_CacheRevalidate(t *testing.T) {
+	var mu sync.Mutex
+	var conditional, full int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		_, _ = w.Write([]byte(`{"id":1,"email":"user@example.com"}`))
+	}))
+	defer srv.Close()
+
+	cache := &lynx.Cache{TTL: time.Hour}
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthApiKey{Key: "a"}, APIBase: srv.URL, Cache: cache})
+	for range 3 {
+		u, err := c.Me()
+		if err != nil {
+			t.Fatalf("Me() error = %v", err)
+		}
+		if u.Email != "user@example.com" {
+			t.Errorf("Me() = %+v", u)
+		}
+	}
+	if full != 1 || conditional != 2 {
+		t.Errorf("full, conditional requests = %d, %d, want 1, 2", full, conditional)
+	}
+
+	// Responses are not shared between identities, here a new token for every request
+	c = lynx.NewClient(&lynx.Options{Authenticator: lynx.NewRefreshingAuth(&tokenSource{}), APIBase: srv.URL, Cache: cache})
+	for range 2 {
+		if _, err := c.Me(); err != nil {
+			t.Fatalf("Me() error = %v", err)
+		}
+	}
+	if full != 3 {
+		t.Errorf("full requests = %d, want 3", full)
+	}
+}
+
+func TestClient_CacheTTL(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	fn := srv.AddFunction(&lynx.Function{InstallationID: 1, Type: "temperature"})
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, APIBase: srv.URL, Cache: &lynx.Cache{TTL: time.Hour}})
+
+	gets := func() int {
+		n := 0
+		for _, r := range srv.Requests() {
+			if r.Method == http.MethodGet {
+				n++
+			}
+		}
+		return n
+	}
+	for range 3 {
+		if _, err := c.GetFunctions(1, lynx.Filter{}); err != nil {
+			t.Fatalf("GetFunctions() error = %v", err)
+		}
+	}
+	if n := gets(); n != 1 {
+		t.Errorf("GET requests = %d, want 1", n)
+	}
+
+	fn.Type = "humidity"
+	if _, err := c.UpdateFunction(fn); err != nil {
+		t.Fatalf("UpdateFunction() error = %v", err)
+	}
+	functions, err := c.GetFunctions(1, lynx.Filter{})
+	if err != nil {
+		t.Fatalf("GetFunctions() error = %v", err)
+	}
+	if len(functions) != 1 || functions[0].Type != "humidity" {
+		t.Errorf("GetFunctions() after update = %+v, want updated function", functions)
+	}
+
+	c.ClearCache()
+	if _, err := c.GetFunctions(1, lynx.Filter{}); err != nil {
+		t.Fatalf("GetFunctions() error = %v", err)
+	}
+	if n := gets(); n != 3 {
+		t.Errorf("GET requests = %d, want 3", n)
+	}
+}
+
+func TestClient_CacheInstallationInfo(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	i := srv.AddInstallation(&lynx.InstallationRow{Name: "old", ClientID: 1, OrganizationID: 1})
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, APIBase: srv.URL, Cache: &lynx.Cache{TTL: time.Hour}})
+
+	if _, err := c.GetInstallations(false); err != nil {
+		t.Fatalf("GetInstallations() error = %v", err)
+	}
+	// Installations are updated under api/v2/installation but listed under api/v2/installationinfo
+	updated := *i
+	updated.Name = "new"
+	if _, err := c.UpdateInstallation(&updated); err != nil {
+		t.Fatalf("UpdateInstallation() error = %v", err)
+	}
+	installations, err := c.GetInstallations(false)
+	if err != nil {
+		t.Fatalf("GetInstallations() error = %v", err)
+	}
+	if len(installations) != 1 || installations[0].Name != "new" {
+		t.Errorf("GetInstallations() after update = %+v, want updated installation", installations)
+	}
+}
+
+func TestClient_CacheExcludesPing(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, APIBase: srv.URL, Cache: &lynx.Cache{TTL: time.Hour}})
+
+	for range 3 {
+		if err := c.Ping(); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("requests = %d, want 3", n)
+	}
+}
@@ -2,18 +2,20 @@ package lynx
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
-	"golang.org/x/net/context"
 	"golang.org/x/net/http2"
 )
 
@@ -23,13 +25,54 @@ type Options struct {
 	APIBase       string
 	MqttOptions   *mqtt.ClientOptions
 	HTTPClient    *http.Client
+	// TLS configures the HTTP/1.1 and h2 transports when HTTPClient is not set (h2c is unencrypted),
+	// and the MQTT client if TLS.MQTT is set. If the files can not be loaded every request fails with the error.
+	TLS *TLSOptions
+	// RetryPolicy enables retries of failed idempotent requests, nil disables retries
+	RetryPolicy *RetryPolicy
+	// RateLimit enables client side rate limiting, nil disables it
+	RateLimit *RateLimit
+	// Cache enables caching of GET responses, nil disables it
+	Cache *Cache
+	// Middleware is applied to every request, the first middleware is the outermost
+	Middleware []Middleware
+	// DecodeErrorHandler is called for received MQTT messages that are not valid Lynx messages,
+	// if nil they are logged
+	DecodeErrorHandler DecodeErrorHandler
+	// ResubscribeErrorHandler is called when a subscription could not be restored after
+	// a reconnect, if nil the failure is logged
+	ResubscribeErrorHandler func(topic string, err error)
+	// Outbox queues messages published while the MQTT client is disconnected, nil disables queueing
+	Outbox *Outbox
+	// MqttConnectTimeout is the time MQTTConnect waits for the connection,
+	// defaults to the connect timeout of MqttOptions
+	MqttConnectTimeout time.Duration
+	// MqttTimeout is the time Publish, Subscribe and Unsubscribe wait for the broker to
+	// acknowledge, defaults to 1s. PublishAll waits this long for each message, or until
+	// all messages are acknowledged if it is not set.
+	MqttTimeout time.Duration
 }
 
 // Client is the main client for Lynx integration
 type Client struct {
-	opt  *Options
-	c    *http.Client
-	Mqtt mqtt.Client
+	opt       *Options
+	c         *http.Client
+	transport RoundTripFunc
+	limiter   *rateLimiter
+	cache     *responseCache
+	// subMu guards subs, the subscriptions per topic filter, and subID, the last subscription id
+	subMu sync.Mutex
+	subs  map[string]*filterSubscriptions
+	subID uint64
+	// clientIDMu guards clientIDs, a cache of installation client ids used as MQTT topic prefix
+	clientIDMu sync.Mutex
+	clientIDs  map[int64]int64
+	// rpcMu guards replies, the subscription and waiting requests per reply topic.
+	// rpcSubMu serializes subscribing to reply topics.
+	rpcMu    sync.Mutex
+	rpcSubMu sync.Mutex
+	replies  map[string]*replyWaiters
+	Mqtt     mqtt.Client
 }
 
 // V3Client is a client implementing the V3 endpoints
@@ -45,62 +88,101 @@ func (c *Client) V3() *V3Client {
 // NewClient create a new client for V2 API:s with specified options
 func NewClient(options *Options) *Client {
 	options.APIBase = strings.TrimSuffix(options.APIBase, "/")
-	var mq mqtt.Client
-	if options.MqttOptions != nil {
-		options.Authenticator.SetMQTTAuth(options.MqttOptions)
-		mq = mqtt.NewClient(options.MqttOptions)
+	c := &Client{
+		opt:       options,
+		subs:      make(map[string]*filterSubscriptions),
+		clientIDs: make(map[int64]int64),
+		replies:   make(map[string]*replyWaiters),
 	}
-	if options.HTTPClient == nil {
-		options.HTTPClient = &http.Client{
-			Timeout: time.Second * 5,
-			Transport: &http.Transport{
-				TLSHandshakeTimeout: time.Second * 5,
-			},
+	var tlsConfig *tls.Config
+	var tlsErr error
+	if options.TLS != nil {
+		if tlsConfig, tlsErr = options.TLS.Config(); tlsErr != nil {
+			tlsErr = fmt.Errorf("invalid TLS options: %w", tlsErr)
 		}
-		if tmp, err := url.Parse(options.APIBase); err == nil && (tmp.Scheme == "h2c" || tmp.Scheme == "h2") {
-			tr := http2.Transport{}
-			if tmp.Scheme == "h2c" {
-				tmp.Scheme = "http"
-				tr.AllowHTTP = true
-				tr.DialTLS = func(network, addr string, cfg *tls.Config) (net.Conn, error) {
-					var d net.Dialer
-					return d.Dial(network, addr)
-				}
-				tr.DialTLSContext = func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
-					var d net.Dialer
-					return d.DialContext(ctx, network, tmp.Host)
-				}
+	}
+	if options.MqttOptions != nil {
+		if options.TLS != nil && options.TLS.MQTT && options.MqttOptions.TLSConfig == nil {
+			if tlsErr != nil {
+				log.Println("MQTT:", tlsErr.Error())
 			} else {
-				tmp.Scheme = "https"
+				options.MqttOptions.SetTLSConfig(tlsConfig.Clone())
 			}
-			options.APIBase = tmp.String()
-			options.HTTPClient.Transport = &tr
 		}
+		options.Authenticator.SetMQTTAuth(options.MqttOptions)
+		onConnect := options.MqttOptions.OnConnect
+		options.MqttOptions.SetOnConnectHandler(func(mq mqtt.Client) {
+			c.resubscribe()
+			if options.Outbox != nil {
+				c.flushOutbox()
+			}
+			if onConnect != nil {
+				onConnect(mq)
+			}
+		})
+		c.Mqtt = mqtt.NewClient(options.MqttOptions)
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient, options.APIBase = newHTTPClient(options.APIBase, httpConfig{TLS: tlsConfig})
 	}
-	return &Client{
-		c:    options.HTTPClient,
-		opt:  options,
-		Mqtt: mq,
+	c.c = options.HTTPClient
+	c.transport = chainMiddleware(options.Middleware, options.HTTPClient.Do)
+	if tlsErr != nil {
+		// NewClient can not fail, so every request reports the error instead
+		c.transport = func(*http.Request) (*http.Response, error) {
+			return nil, tlsErr
+		}
 	}
+	c.limiter = newRateLimiter(options.RateLimit)
+	c.cache = newResponseCache(options.Cache)
+	return c
 }
 
-func requestError(response *http.Response) error {
-	if response.StatusCode != http.StatusOK {
-		err := Error{
-			Code: response.StatusCode,
-		}
-		if response.StatusCode == http.StatusRequestURITooLong {
-			return err
-		}
-		if response.StatusCode != http.StatusNoContent {
-			jsonError := json.NewDecoder(response.Body).Decode(&err)
-			if jsonError != nil {
-				return jsonError
+// httpConfig configures the default HTTP client, zero values use the defaults
+type httpConfig struct {
+	Timeout             time.Duration
+	TLSHandshakeTimeout time.Duration
+	TLS                 *tls.Config
+	Proxy               func(*http.Request) (*url.URL, error)
+}
+
+// newHTTPClient returns the default HTTP client for apiBase together with the API base to use.
+// The h2 and h2c schemes select HTTP/2 over TLS and HTTP/2 without TLS.
+func newHTTPClient(apiBase string, conf httpConfig) (*http.Client, string) {
+	if conf.Timeout <= 0 {
+		conf.Timeout = time.Second * 5
+	}
+	if conf.TLSHandshakeTimeout <= 0 {
+		conf.TLSHandshakeTimeout = time.Second * 5
+	}
+	client := &http.Client{
+		Timeout: conf.Timeout,
+		Transport: &http.Transport{
+			Proxy:               conf.Proxy,
+			TLSClientConfig:     conf.TLS,
+			TLSHandshakeTimeout: conf.TLSHandshakeTimeout,
+		},
+	}
+	if tmp, err := url.Parse(apiBase); err == nil && (tmp.Scheme == "h2c" || tmp.Scheme == "h2") {
+		tr := http2.Transport{TLSClientConfig: conf.TLS}
+		if tmp.Scheme == "h2c" {
+			tmp.Scheme = "http"
+			tr.AllowHTTP = true
+			tr.DialTLS = func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.Dial(network, addr)
+			}
+			tr.DialTLSContext = func(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, tmp.Host)
 			}
+		} else {
+			tmp.Scheme = "https"
 		}
-		return err
+		apiBase = tmp.String()
+		client.Transport = &tr
 	}
-	return nil
+	return client, apiBase
 }
 
 func requestBody(data interface{}) io.Reader {
@@ -109,8 +191,118 @@ func requestBody(data interface{}) io.Reader {
 	return body
 }
 
+// send performs the request, renewing rejected credentials once and retrying it according to the retry policy.
+// The returned response has not been checked for errors.
+func (c *Client) send(r *http.Request) (*http.Response, error) {
+	auth, ok := c.opt.Authenticator.(renewer)
+	if !ok {
+		return c.retry(r)
+	}
+	if err := auth.authorize(r); err != nil {
+		return nil, err
+	}
+	response, err := c.retry(r)
+	if err != nil || response.StatusCode != http.StatusUnauthorized || (r.Body != nil && r.GetBody == nil) {
+		return response, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+	response.Body.Close()
+	auth.renew(r)
+	if err := auth.authorize(r); err != nil {
+		return nil, err
+	}
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return c.retry(r)
+}
+
+// retry performs the request, retrying it according to the retry policy.
+func (c *Client) retry(r *http.Request) (*http.Response, error) {
+	policy := c.opt.RetryPolicy
+	if !policy.canRetry(r) {
+		return c.roundTrip(r)
+	}
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		response, err := c.roundTrip(r)
+		last := attempt >= policy.MaxAttempts
+		if err != nil {
+			if last || r.Context().Err() != nil {
+				return nil, err
+			}
+		} else if last || !policy.retryStatus(response.StatusCode) {
+			return response, nil
+		} else {
+			_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+			response.Body.Close()
+		}
+		if err := sleepContext(r.Context(), policy.backoff(attempt, response)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTrip performs a single attempt of the request, using the cache and respecting the rate limit.
+func (c *Client) roundTrip(r *http.Request) (*http.Response, error) {
+	if c.cache == nil {
+		return c.limitedRoundTrip(r)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return c.cache.roundTrip(r, c.limitedRoundTrip)
+	case http.MethodHead, http.MethodOptions:
+		return c.limitedRoundTrip(r)
+	}
+	response, err := c.limitedRoundTrip(r)
+	c.cache.invalidate(r.URL.Path)
+	return response, err
+}
+
+// limitedRoundTrip performs a single attempt of the request, respecting the rate limit.
+func (c *Client) limitedRoundTrip(r *http.Request) (*http.Response, error) {
+	if c.limiter == nil {
+		return c.transport(r)
+	}
+	if err := c.limiter.wait(r.Context()); err != nil {
+		return nil, err
+	}
+	response, err := c.transport(r)
+	if err != nil {
+		c.limiter.done()
+		return nil, err
+	}
+	c.limiter.observe(response)
+	// The request is in flight until its body is closed, which matters for streamed downloads
+	response.Body = &limitedBody{ReadCloser: response.Body, done: c.limiter.done}
+	return response, nil
+}
+
+// limitedBody releases the in-flight slot of the rate limiter when the body is closed.
+type limitedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *limitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
+
 func (c *Client) do(r *http.Request, out interface{}) error {
-	response, err := c.c.Do(r)
+	response, err := c.send(r)
 	if err != nil {
 		return err
 	}
@@ -126,16 +318,24 @@ func (c *Client) do(r *http.Request, out interface{}) error {
 	return nil
 }
 
-func (c *Client) newRequest(method, path string, body io.Reader) *http.Request {
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) *http.Request {
 	uri := fmt.Sprintf("%s/%s", c.opt.APIBase, path)
-	r, _ := http.NewRequest(method, uri, body)
-	c.opt.Authenticator.SetHTTPAuth(r)
+	r, _ := http.NewRequestWithContext(ctx, method, uri, body)
+	// Renewable credentials are set by send, where errors obtaining them can be returned
+	if _, ok := c.opt.Authenticator.(renewer); !ok {
+		c.opt.Authenticator.SetHTTPAuth(r)
+	}
 	return r
 }
 
 // Ping verify that the api is responding
 func (c *Client) Ping() error {
-	request := c.newRequest(http.MethodGet, "/api/v2/ping", nil)
+	return c.PingContext(context.Background())
+}
+
+// PingContext is like Ping but uses ctx for the underlying requests.
+func (c *Client) PingContext(ctx context.Context) error {
+	request := c.newRequest(ctx, http.MethodGet, "api/v2/ping", nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
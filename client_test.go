@@ -0,0 +1,91 @@
+package lynx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_RetryPolicy(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"id":1,"type":"","installation_id":2,"meta":null,"protected_meta":null,"created":0,"updated":0}` {
+			t.Errorf("unexpected body on attempt %d: %s", attempts, body)
+		}
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MinBackoff = time.Millisecond
+	c := NewClient(&Options{
+		Authenticator: AuthNone{},
+		APIBase:       srv.URL,
+		RetryPolicy:   policy,
+	})
+	fn, err := c.UpdateFunction(&Function{ID: 1, InstallationID: 2})
+	if err != nil {
+		t.Fatalf("UpdateFunction() error = %v", err)
+	}
+	if fn.ID != 1 || attempts != 3 {
+		t.Errorf("UpdateFunction() = %v after %d attempts, want id 1 after 3", fn.ID, attempts)
+	}
+
+	attempts = 0
+	if _, err := c.CreateFunction(&Function{ID: 1, InstallationID: 2}); err == nil {
+		t.Errorf("CreateFunction() expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("CreateFunction() made %d attempts, want 1", attempts)
+	}
+}
+
+func TestClient_Middleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Request-ID") != "abc" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				r.Header.Set("X-Request-ID", "abc")
+				return next(r)
+			}
+		}
+	}
+	var status int
+	c := NewClient(&Options{
+		Authenticator: AuthNone{},
+		APIBase:       srv.URL,
+		Middleware: []Middleware{
+			tag("first"),
+			tag("second"),
+			LatencyMiddleware(func(method, path string, s int, d time.Duration) {
+				status = s
+			}),
+		},
+	})
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("middleware order = %v", order)
+	}
+	if status != http.StatusOK {
+		t.Errorf("LatencyMiddleware status = %d, want %d", status, http.StatusOK)
+	}
+}
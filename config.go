@@ -0,0 +1,145 @@
+package lynx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// NewClientFromConfig creates a client from a viper config which can include:
+// api_base, the URL of the Lynx API, required. The h2 and h2c schemes select HTTP/2
+// auth, the credentials read by ViperCredentials, or auth.file and auth.profile for a credentials
+// file. Defaults to DefaultCredentials. OAuth2 token requests use the http and tls settings
+// http.timeout and http.tls_handshake_timeout, both default to 5s
+// http.proxy, URL of an HTTP proxy, or "environment" for the HTTP_PROXY environment variables
+// tls.ca_file, tls.cert_file, tls.key_file, tls.min_version, tls.server_name and tls.insecure_skip_verify,
+// tls.mqtt uses the same settings for MQTT unless the mqtt section has its own tls section
+// retry.max_attempts, retry.min_backoff, retry.max_backoff, retry.status and retry.non_idempotent,
+// unset values use DefaultRetryPolicy. Retries are disabled if there is no retry section
+// mqtt, the MQTT configuration read by NewMqttOptions, MQTT is disabled if there is no mqtt section
+// All invalid settings are reported in the returned error.
+func NewClientFromConfig(conf *viper.Viper) (*Client, error) {
+	var errs []error
+	invalid := func(key string, err error) {
+		errs = append(errs, fmt.Errorf("%s: %w", key, err))
+	}
+	// duration returns the positive duration at key, which must have a unit such as 5s, or 0 if it is not set
+	duration := func(key string) time.Duration {
+		if !conf.IsSet(key) {
+			return 0
+		}
+		value := conf.Get(key)
+		if d, ok := value.(time.Duration); ok && d > 0 {
+			return d
+		}
+		s, ok := value.(string)
+		if !ok || strings.TrimLeft(s, "+-0123456789.") == "" {
+			invalid(key, fmt.Errorf("%v is not a duration with a unit, such as 5s", value))
+			return 0
+		}
+		d, err := cast.ToDurationE(s)
+		if err != nil {
+			invalid(key, err)
+			return 0
+		}
+		if d <= 0 {
+			invalid(key, fmt.Errorf("%s is not positive", s))
+			return 0
+		}
+		return d
+	}
+	options := &Options{APIBase: conf.GetString("api_base")}
+
+	base, err := url.Parse(options.APIBase)
+	switch {
+	case options.APIBase == "":
+		invalid("api_base", errors.New("not set"))
+	case err != nil:
+		invalid("api_base", err)
+	case !slices.Contains([]string{"http", "https", "h2", "h2c"}, base.Scheme) || base.Host == "":
+		invalid("api_base", fmt.Errorf("%q is not an absolute http, https, h2 or h2c URL", options.APIBase))
+	}
+
+	httpConf := httpConfig{
+		Timeout:             duration("http.timeout"),
+		TLSHandshakeTimeout: duration("http.tls_handshake_timeout"),
+	}
+	switch proxy := conf.GetString("http.proxy"); proxy {
+	case "":
+	case "environment":
+		httpConf.Proxy = http.ProxyFromEnvironment
+	default:
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			invalid("http.proxy", fmt.Errorf("%q is not an absolute URL", proxy))
+		} else if base != nil && (base.Scheme == "h2" || base.Scheme == "h2c") {
+			invalid("http.proxy", fmt.Errorf("proxies are not supported with %s", base.Scheme))
+		} else {
+			httpConf.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	if conf.IsSet("tls") {
+		if options.TLS, err = tlsOptionsFromConfig(conf); err == nil {
+			httpConf.TLS, err = options.TLS.Config()
+		}
+		if err != nil {
+			invalid("tls", err)
+		}
+	}
+
+	// Token requests use the same TLS, proxy and timeout settings as the API, but never HTTP/2 without TLS
+	tokenClient, _ := newHTTPClient("", httpConf)
+	provider := defaultCredentials(tokenClient)
+	if conf.IsSet("auth.file") || conf.IsSet("auth.profile") {
+		provider = FileCredentials{Path: conf.GetString("auth.file"), Profile: conf.GetString("auth.profile"), HTTPClient: tokenClient}
+	} else if conf.IsSet("auth") {
+		provider = ViperCredentials{Conf: conf.Sub("auth"), HTTPClient: tokenClient}
+	}
+	if options.Authenticator, err = provider.Authentication(); err != nil {
+		invalid("auth", err)
+	}
+
+	if conf.IsSet("retry") {
+		options.RetryPolicy = DefaultRetryPolicy()
+		if conf.IsSet("retry.max_attempts") {
+			options.RetryPolicy.MaxAttempts = conf.GetInt("retry.max_attempts")
+		}
+		if d := duration("retry.min_backoff"); d > 0 {
+			options.RetryPolicy.MinBackoff = d
+		}
+		if d := duration("retry.max_backoff"); d > 0 {
+			options.RetryPolicy.MaxBackoff = d
+		}
+		if conf.IsSet("retry.status") {
+			options.RetryPolicy.RetryableStatus = conf.GetIntSlice("retry.status")
+		}
+		options.RetryPolicy.RetryNonIdempotent = conf.GetBool("retry.non_idempotent")
+		if options.RetryPolicy.MinBackoff > options.RetryPolicy.MaxBackoff {
+			invalid("retry", errors.New("min_backoff is larger than max_backoff"))
+		}
+	}
+
+	if conf.IsSet("mqtt") {
+		mqttConf := conf.Sub("mqtt")
+		if mqttConf == nil {
+			invalid("mqtt", errors.New("not a section"))
+		} else if mqttConf.GetString("broker") == "" {
+			invalid("mqtt.broker", errors.New("not set"))
+		} else if options.MqttOptions, err = MqttOptionsFromConfig(mqttConf, nil, nil); err != nil {
+			invalid("mqtt", err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid client config: %w", errors.Join(errs...))
+	}
+	options.HTTPClient, options.APIBase = newHTTPClient(options.APIBase, httpConf)
+	return NewClient(options), nil
+}
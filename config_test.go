@@ -0,0 +1,137 @@
+package lynx_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+	"github.com/spf13/viper"
+)
+
+func readConfig(t *testing.T, config string) *viper.Viper {
+	t.Helper()
+	conf := viper.New()
+	conf.SetConfigType("yaml")
+	if err := conf.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+	return conf
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	b := lynxtest.NewTestBroker(t)
+
+	conf := readConfig(t, `
+api_base: `+srv.URL+`
+auth:
+  api_key: secret
+http:
+  timeout: 10s
+retry:
+  max_attempts: 2
+mqtt:
+  broker: `+b.URL()+`
+  client_id: config-test
+`)
+	c, err := lynx.NewClientFromConfig(conf)
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+	if err := c.MQTTConnect(); err != nil {
+		t.Fatalf("MQTTConnect() error = %v", err)
+	}
+	c.MQTTDisconnect()
+}
+
+func TestNewClientFromConfig_TokenTLS(t *testing.T) {
+	// Token and API requests are served over TLS with a certificate only trusted through tls.ca_file
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"abc","token_type":"bearer","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := lynx.NewClientFromConfig(readConfig(t, `
+api_base: `+srv.URL+`
+auth:
+  client_id: service
+  client_secret: secret
+  token_url: `+srv.URL+`/token
+tls:
+  ca_file: `+caFile+`
+`))
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
+
+func TestNewClientFromConfig_Invalid(t *testing.T) {
+	conf := readConfig(t, `
+api_base: ftp://example.com
+auth:
+  client_id: service
+http:
+  proxy: "::"
+tls:
+  ca_file: /does/not/exist
+retry:
+  min_backoff: 10s
+  max_backoff: 1s
+mqtt:
+  client_id: test
+`)
+	_, err := lynx.NewClientFromConfig(conf)
+	if err == nil {
+		t.Fatal("NewClientFromConfig() error = nil")
+	}
+	for _, key := range []string{"api_base:", "auth:", "http.proxy:", "tls:", "retry:", "mqtt.broker:"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("NewClientFromConfig() error = %v, want error for %s", err, key)
+		}
+	}
+
+	// Durations must have a unit and be positive
+	conf = readConfig(t, `
+api_base: https://example.com
+auth:
+  api_key: secret
+http:
+  timeout: 5
+  tls_handshake_timeout: abc
+retry:
+  min_backoff: -1s
+  max_backoff: 0s
+`)
+	_, err = lynx.NewClientFromConfig(conf)
+	if err == nil {
+		t.Fatal("NewClientFromConfig() with invalid durations error = nil")
+	}
+	for _, key := range []string{"http.timeout:", "http.tls_handshake_timeout:", "retry.min_backoff:", "retry.max_backoff:"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("NewClientFromConfig() error = %v, want error for %s", err, key)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package lynx
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/viper"
+)
+
+// ErrNoCredentials is returned by a CredentialProvider that has no credentials configured
+var ErrNoCredentials = errors.New("no credentials")
+
+// DefaultProfile is the profile of the credentials file used if none is given
+const DefaultProfile = "default"
+
+// CredentialProvider creates the Authentication for a client from external configuration.
+//
+// The configuration is a set of keys, the first complete set decides the Authentication:
+// token (AuthBearer), api_key (AuthApiKey), client_id, client_secret and token_url with
+// optional scopes (ClientCredentials with RefreshingAuth), username and password (Basic).
+type CredentialProvider interface {
+	Authentication() (Authentication, error)
+}
+
+// authFromValues creates the Authentication for the keys returned by get, client is used for token requests.
+func authFromValues(get func(key string) string, client *http.Client) (Authentication, error) {
+	switch {
+	case get("token") != "":
+		return AuthBearer{Token: get("token")}, nil
+	case get("api_key") != "":
+		return AuthApiKey{Key: get("api_key")}, nil
+	case get("client_id") != "" || get("client_secret") != "":
+		if get("client_id") == "" || get("client_secret") == "" || get("token_url") == "" {
+			return nil, fmt.Errorf("client_id, client_secret and token_url must all be set")
+		}
+		scopes := strings.Fields(strings.ReplaceAll(get("scopes"), ",", " "))
+		return NewRefreshingAuth(&ClientCredentials{
+			TokenURL:     get("token_url"),
+			ClientID:     get("client_id"),
+			ClientSecret: get("client_secret"),
+			Scopes:       scopes,
+			HTTPClient:   client,
+		}), nil
+	case get("username") != "":
+		return Basic{User: get("username"), Password: get("password")}, nil
+	}
+	return nil, ErrNoCredentials
+}
+
+// EnvCredentials reads credentials from environment variables named by the upper case keys
+// with a prefix, LYNX_ if Prefix is empty, for example LYNX_API_KEY.
+type EnvCredentials struct {
+	Prefix string
+	// HTTPClient is used for OAuth2 token requests, see ClientCredentials.HTTPClient
+	HTTPClient *http.Client
+}
+
+func (e EnvCredentials) Authentication() (Authentication, error) {
+	prefix := e.Prefix
+	if prefix == "" {
+		prefix = "LYNX_"
+	}
+	return authFromValues(func(key string) string {
+		return os.Getenv(prefix + strings.ToUpper(key))
+	}, e.HTTPClient)
+}
+
+// ViperCredentials reads credentials from a viper config, usually a sub config such as conf.Sub("auth").
+type ViperCredentials struct {
+	Conf *viper.Viper
+	// HTTPClient is used for OAuth2 token requests, see ClientCredentials.HTTPClient
+	HTTPClient *http.Client
+}
+
+func (v ViperCredentials) Authentication() (Authentication, error) {
+	if v.Conf == nil {
+		return nil, ErrNoCredentials
+	}
+	return authFromValues(func(key string) string {
+		if key == "scopes" {
+			return strings.Join(v.Conf.GetStringSlice(key), " ")
+		}
+		return v.Conf.GetString(key)
+	}, v.HTTPClient)
+}
+
+// FileCredentials reads credentials from a profile in a credentials file with sections:
+//
+//	[default]
+//	api_key = ...
+//
+//	[staging]
+//	client_id = ...
+//	client_secret = ...
+//	token_url = ...
+//
+// The returned Authentication re-reads the file when it changes, so rotated secrets are
+// used without restarting.
+type FileCredentials struct {
+	// Path of the file, defaults to ~/.lynx/credentials
+	Path string
+	// Profile is the section to use, defaults to the LYNX_PROFILE environment variable or DefaultProfile
+	Profile string
+	// HTTPClient is used for OAuth2 token requests, see ClientCredentials.HTTPClient
+	HTTPClient *http.Client
+}
+
+// DefaultCredentialsPath returns the path of the credentials file in the home directory, ~/.lynx/credentials.
+func DefaultCredentialsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".lynx", "credentials"), nil
+}
+
+func (f FileCredentials) Authentication() (Authentication, error) {
+	path := f.Path
+	if path == "" {
+		var err error
+		if path, err = DefaultCredentialsPath(); err != nil {
+			return nil, err
+		}
+	}
+	profile := f.Profile
+	if profile == "" {
+		profile = os.Getenv("LYNX_PROFILE")
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	a := &fileAuth{path: path, profile: profile, client: f.HTTPClient}
+	if _, err := a.current(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNoCredentials, err.Error())
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// ChainCredentials returns a CredentialProvider using the first provider that has credentials.
+func ChainCredentials(providers ...CredentialProvider) CredentialProvider {
+	return chainCredentials(providers)
+}
+
+type chainCredentials []CredentialProvider
+
+func (c chainCredentials) Authentication() (Authentication, error) {
+	for _, p := range c {
+		auth, err := p.Authentication()
+		if !errors.Is(err, ErrNoCredentials) {
+			return auth, err
+		}
+	}
+	return nil, ErrNoCredentials
+}
+
+// DefaultCredentials returns a CredentialProvider reading credentials from the LYNX_ environment
+// variables, or if none are set from the default credentials file.
+func DefaultCredentials() CredentialProvider {
+	return defaultCredentials(nil)
+}
+
+// defaultCredentials is DefaultCredentials using client for OAuth2 token requests.
+func defaultCredentials(client *http.Client) CredentialProvider {
+	return ChainCredentials(EnvCredentials{HTTPClient: client}, FileCredentials{HTTPClient: client})
+}
+
+// parseCredentials returns the keys of the profile section in an ini style credentials file.
+func parseCredentials(data []byte, profile string) (map[string]string, error) {
+	var res map[string]string
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == profile && res == nil {
+				res = make(map[string]string)
+			}
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		if section == profile {
+			res[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	if res == nil {
+		return nil, fmt.Errorf("%w: profile %s not found", ErrNoCredentials, profile)
+	}
+	return res, nil
+}
+
+// fileAuth is the Authentication of a credentials file, reloaded when the file changes.
+type fileAuth struct {
+	path    string
+	profile string
+	client  *http.Client
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	auth    Authentication
+}
+
+// current returns the Authentication of the file, reloading it if the file has changed.
+// If reloading fails the previous Authentication is kept.
+func (a *fileAuth) current() (Authentication, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	info, err := os.Stat(a.path)
+	if err != nil {
+		if a.auth != nil {
+			return a.auth, nil
+		}
+		return nil, err
+	}
+	if a.auth != nil && info.ModTime().Equal(a.modTime) && info.Size() == a.size {
+		return a.auth, nil
+	}
+	auth, err := a.load()
+	if err != nil {
+		if a.auth != nil {
+			return a.auth, nil
+		}
+		return nil, err
+	}
+	a.auth, a.modTime, a.size = auth, info.ModTime(), info.Size()
+	return auth, nil
+}
+
+func (a *fileAuth) load() (Authentication, error) {
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := parseCredentials(data, a.profile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a.path, err)
+	}
+	auth, err := authFromValues(func(key string) string { return values[key] }, a.client)
+	if err != nil {
+		return nil, fmt.Errorf("%s: profile %s: %w", a.path, a.profile, err)
+	}
+	return auth, nil
+}
+
+func (a *fileAuth) authorize(r *http.Request) error {
+	auth, err := a.current()
+	if err != nil {
+		return err
+	}
+	if rn, ok := auth.(renewer); ok {
+		return rn.authorize(r)
+	}
+	auth.SetHTTPAuth(r)
+	return nil
+}
+
+func (a *fileAuth) renew(r *http.Request) {
+	if auth, err := a.current(); err == nil {
+		if rn, ok := auth.(renewer); ok {
+			rn.renew(r)
+		}
+	}
+}
+
+func (a *fileAuth) SetHTTPAuth(r *http.Request) {
+	_ = a.authorize(r)
+}
+
+// SetMQTTAuth makes the MQTT client use the current credentials of the file on every connect.
+func (a *fileAuth) SetMQTTAuth(o *mqtt.ClientOptions) {
+	o.SetCredentialsProvider(func() (string, string) {
+		auth, err := a.current()
+		if err != nil {
+			log.Println("MQTT: no credentials:", err.Error())
+			return "", ""
+		}
+		tmp := mqtt.NewClientOptions()
+		auth.SetMQTTAuth(tmp)
+		if tmp.CredentialsProvider != nil {
+			return tmp.CredentialsProvider()
+		}
+		return tmp.Username, tmp.Password
+	})
+}
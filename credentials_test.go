@@ -0,0 +1,119 @@
+package lynx_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/spf13/viper"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	if _, err := (lynx.EnvCredentials{Prefix: "LYNXTEST_"}).Authentication(); !errors.Is(err, lynx.ErrNoCredentials) {
+		t.Errorf("Authentication() without variables error = %v, want ErrNoCredentials", err)
+	}
+	t.Setenv("LYNXTEST_USERNAME", "user")
+	t.Setenv("LYNXTEST_PASSWORD", "secret")
+	auth, err := lynx.EnvCredentials{Prefix: "LYNXTEST_"}.Authentication()
+	if err != nil || auth != (lynx.Basic{User: "user", Password: "secret"}) {
+		t.Errorf("Authentication() = %v, %v, want Basic", auth, err)
+	}
+	t.Setenv("LYNXTEST_API_KEY", "key")
+	auth, err = lynx.EnvCredentials{Prefix: "LYNXTEST_"}.Authentication()
+	if err != nil || auth != (lynx.AuthApiKey{Key: "key"}) {
+		t.Errorf("Authentication() = %v, %v, want AuthApiKey", auth, err)
+	}
+	t.Setenv("LYNXTEST_CLIENT_ID", "service")
+	t.Setenv("LYNXTEST_API_KEY", "")
+	if _, err := (lynx.EnvCredentials{Prefix: "LYNXTEST_"}).Authentication(); err == nil {
+		t.Errorf("Authentication() with incomplete client credentials error = nil")
+	}
+}
+
+func TestViperCredentials(t *testing.T) {
+	conf := viper.New()
+	conf.SetConfigType("yaml")
+	err := conf.ReadConfig(strings.NewReader(`
+auth:
+  client_id: service
+  client_secret: secret
+  token_url: https://auth.example.com/token
+  scopes: [read, write]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := lynx.ViperCredentials{Conf: conf.Sub("auth")}.Authentication()
+	if err != nil {
+		t.Fatalf("Authentication() error = %v", err)
+	}
+	refreshing, ok := auth.(*lynx.RefreshingAuth)
+	if !ok {
+		t.Fatalf("Authentication() = %T, want *RefreshingAuth", auth)
+	}
+	cc := refreshing.Source.(*lynx.ClientCredentials)
+	if cc.ClientID != "service" || cc.TokenURL != "https://auth.example.com/token" || len(cc.Scopes) != 2 {
+		t.Errorf("ClientCredentials = %+v", cc)
+	}
+	if _, err := (lynx.ViperCredentials{Conf: conf.Sub("missing")}).Authentication(); !errors.Is(err, lynx.ErrNoCredentials) {
+		t.Errorf("Authentication() without config error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	write := func(content string, mod time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	header := func(auth lynx.Authentication) string {
+		r, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		auth.SetHTTPAuth(r)
+		return r.Header.Get("X-API-Key")
+	}
+
+	if _, err := (lynx.FileCredentials{Path: path}).Authentication(); !errors.Is(err, lynx.ErrNoCredentials) {
+		t.Errorf("Authentication() without file error = %v, want ErrNoCredentials", err)
+	}
+	now := time.Now()
+	write("# comment\n[default]\napi_key = one\n\n[staging]\napi_key = \"two\"\n", now.Add(-time.Minute))
+	auth, err := lynx.FileCredentials{Path: path}.Authentication()
+	if err != nil {
+		t.Fatalf("Authentication() error = %v", err)
+	}
+	if got := header(auth); got != "one" {
+		t.Errorf("X-API-Key = %q, want one", got)
+	}
+	staging, err := lynx.FileCredentials{Path: path, Profile: "staging"}.Authentication()
+	if err != nil || header(staging) != "two" {
+		t.Errorf("staging X-API-Key = %q, %v, want two", header(staging), err)
+	}
+	if _, err := (lynx.FileCredentials{Path: path, Profile: "prod"}).Authentication(); !errors.Is(err, lynx.ErrNoCredentials) {
+		t.Errorf("Authentication() for missing profile error = %v, want ErrNoCredentials", err)
+	}
+
+	// Rotated secrets are picked up, invalid files keep the previous credentials
+	write("[default]\napi_key = rotated\n", now)
+	if got := header(auth); got != "rotated" {
+		t.Errorf("X-API-Key after rotation = %q, want rotated", got)
+	}
+	write("[default]\ninvalid\n", now.Add(time.Minute))
+	if got := header(auth); got != "rotated" {
+		t.Errorf("X-API-Key after invalid rewrite = %q, want rotated", got)
+	}
+
+	t.Setenv("LYNX_API_KEY", "env")
+	auth, err = lynx.ChainCredentials(lynx.EnvCredentials{}, lynx.FileCredentials{Path: path}).Authentication()
+	if err != nil || header(auth) != "env" {
+		t.Errorf("ChainCredentials() X-API-Key = %q, %v, want env", header(auth), err)
+	}
+}
@@ -1,6 +1,7 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -47,9 +48,13 @@ func (d DeviceList) MapByList(key string) map[string]DeviceList {
 }
 
 func (c *Client) GetDevices(installationID int64, filter Filter) (DeviceList, error) {
+	return c.GetDevicesContext(context.Background(), installationID, filter)
+}
+
+func (c *Client) GetDevicesContext(ctx context.Context, installationID int64, filter Filter) (DeviceList, error) {
 	res := make(DeviceList, 0, 20)
 	query := filter.ToURLValues()
-	request := c.newRequest(http.MethodGet, fmt.Sprintf("api/v2/devicex/%d?%s", installationID, query.Encode()), nil)
+	request := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("api/v2/devicex/%d?%s", installationID, query.Encode()), nil)
 	if err := c.do(request, &res); err != nil {
 		return nil, err
 	}
@@ -57,9 +62,13 @@ func (c *Client) GetDevices(installationID int64, filter Filter) (DeviceList, er
 }
 
 func (c *Client) GetDevice(installationID, deviceID int64) (*Device, error) {
+	return c.GetDeviceContext(context.Background(), installationID, deviceID)
+}
+
+func (c *Client) GetDeviceContext(ctx context.Context, installationID, deviceID int64) (*Device, error) {
 	device := &Device{}
 	path := fmt.Sprintf("api/v2/devicex/%d/%d", installationID, deviceID)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, device); err != nil {
 		return nil, err
 	}
@@ -67,9 +76,13 @@ func (c *Client) GetDevice(installationID, deviceID int64) (*Device, error) {
 }
 
 func (c *Client) CreateDevice(dev *Device) (*Device, error) {
+	return c.CreateDeviceContext(context.Background(), dev)
+}
+
+func (c *Client) CreateDeviceContext(ctx context.Context, dev *Device) (*Device, error) {
 	device := &Device{}
 	path := fmt.Sprintf("api/v2/devicex/%d", dev.InstallationID)
-	request := c.newRequest(http.MethodPost, path, requestBody(dev))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(dev))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, device); err != nil {
 		return nil, err
@@ -78,8 +91,12 @@ func (c *Client) CreateDevice(dev *Device) (*Device, error) {
 }
 
 func (c *Client) DeleteDevice(dev *Device) error {
+	return c.DeleteDeviceContext(context.Background(), dev)
+}
+
+func (c *Client) DeleteDeviceContext(ctx context.Context, dev *Device) error {
 	path := fmt.Sprintf("api/v2/devicex/%d/%d", dev.InstallationID, dev.ID)
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
@@ -87,9 +104,13 @@ func (c *Client) DeleteDevice(dev *Device) error {
 }
 
 func (c *Client) UpdateDevice(dev *Device) (*Device, error) {
+	return c.UpdateDeviceContext(context.Background(), dev)
+}
+
+func (c *Client) UpdateDeviceContext(ctx context.Context, dev *Device) (*Device, error) {
 	device := &Device{}
 	path := fmt.Sprintf("api/v2/devicex/%d/%d", dev.InstallationID, dev.ID)
-	request := c.newRequest(http.MethodPut, path, requestBody(dev))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(dev))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, device); err != nil {
 		return nil, err
@@ -98,9 +119,13 @@ func (c *Client) UpdateDevice(dev *Device) (*Device, error) {
 }
 
 func (c *Client) GetDeviceMeta(installationID, deviceID int64, key string) (*MetaObject, error) {
+	return c.GetDeviceMetaContext(context.Background(), installationID, deviceID, key)
+}
+
+func (c *Client) GetDeviceMetaContext(ctx context.Context, installationID, deviceID int64, key string) (*MetaObject, error) {
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/devicex/%d/%d/meta/%s", installationID, deviceID, key)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, mo); err != nil {
 		return nil, err
 	}
@@ -108,12 +133,16 @@ func (c *Client) GetDeviceMeta(installationID, deviceID int64, key string) (*Met
 }
 
 func (c *Client) CreateDeviceMeta(installationID, deviceID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
+	return c.CreateDeviceMetaContext(context.Background(), installationID, deviceID, key, meta, silent)
+}
+
+func (c *Client) CreateDeviceMetaContext(ctx context.Context, installationID, deviceID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/devicex/%d/%d/meta/%s?%s", installationID, deviceID, key, query.Encode())
-	request := c.newRequest(http.MethodPost, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -122,13 +151,17 @@ func (c *Client) CreateDeviceMeta(installationID, deviceID int64, key string, me
 }
 
 func (c *Client) UpdateDeviceMeta(installationID, deviceID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
+	return c.UpdateDeviceMetaContext(context.Background(), installationID, deviceID, key, meta, silent, createMissing)
+}
+
+func (c *Client) UpdateDeviceMetaContext(ctx context.Context, installationID, deviceID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent":         []string{fmt.Sprintf("%t", silent)},
 		"create_missing": []string{fmt.Sprintf("%t", createMissing)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/devicex/%d/%d/meta/%s?%s", installationID, deviceID, key, query.Encode())
-	request := c.newRequest(http.MethodPut, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -137,11 +170,15 @@ func (c *Client) UpdateDeviceMeta(installationID, deviceID int64, key string, me
 }
 
 func (c *Client) DeleteDeviceMeta(installationID, deviceID int64, key string, silent bool) error {
+	return c.DeleteDeviceMetaContext(context.Background(), installationID, deviceID, key, silent)
+}
+
+func (c *Client) DeleteDeviceMetaContext(ctx context.Context, installationID, deviceID int64, key string, silent bool) error {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	path := fmt.Sprintf("api/v2/devicex/%d/%d/meta/%s?%s", installationID, deviceID, key, query.Encode())
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package lynx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -47,9 +48,13 @@ type EdgeAppConfig struct {
 }
 
 func (c *Client) GetEdgeApps() ([]*EdgeApp, error) {
+	return c.GetEdgeAppsContext(context.Background())
+}
+
+func (c *Client) GetEdgeAppsContext(ctx context.Context) ([]*EdgeApp, error) {
 	res := make([]*EdgeApp, 0, 10)
 	path := "api/v2/edge/app"
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -57,13 +62,17 @@ func (c *Client) GetEdgeApps() ([]*EdgeApp, error) {
 }
 
 func (c *Client) GetEdgeAppsOrganization(organizationID int64, available bool) ([]*EdgeApp, error) {
+	return c.GetEdgeAppsOrganizationContext(context.Background(), organizationID, available)
+}
+
+func (c *Client) GetEdgeAppsOrganizationContext(ctx context.Context, organizationID int64, available bool) ([]*EdgeApp, error) {
 	res := make([]*EdgeApp, 0, 10)
 	qs := ""
 	if available {
 		qs = "?available=true"
 	}
 	path := fmt.Sprintf("api/v2/edge/app/organization/%d%s", organizationID, qs)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -71,9 +80,13 @@ func (c *Client) GetEdgeAppsOrganization(organizationID int64, available bool) (
 }
 
 func (c *Client) GetEdgeApp(id int64) (*EdgeApp, error) {
+	return c.GetEdgeAppContext(context.Background(), id)
+}
+
+func (c *Client) GetEdgeAppContext(ctx context.Context, id int64) (*EdgeApp, error) {
 	res := &EdgeApp{}
 	path := fmt.Sprintf("api/v2/edge/app/%d", id)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
@@ -81,9 +94,13 @@ func (c *Client) GetEdgeApp(id int64) (*EdgeApp, error) {
 }
 
 func (c *Client) CreateEdgeApp(app *EdgeApp) (*EdgeApp, error) {
+	return c.CreateEdgeAppContext(context.Background(), app)
+}
+
+func (c *Client) CreateEdgeAppContext(ctx context.Context, app *EdgeApp) (*EdgeApp, error) {
 	res := &EdgeApp{}
 	path := "api/v2/edge/app"
-	req := c.newRequest(http.MethodPost, path, requestBody(app))
+	req := c.newRequest(ctx, http.MethodPost, path, requestBody(app))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, res); err != nil {
 		return nil, err
@@ -92,9 +109,13 @@ func (c *Client) CreateEdgeApp(app *EdgeApp) (*EdgeApp, error) {
 }
 
 func (c *Client) DownloadEdgeApp(id int64, version string) ([]byte, error) {
+	return c.DownloadEdgeAppContext(context.Background(), id, version)
+}
+
+func (c *Client) DownloadEdgeAppContext(ctx context.Context, id int64, version string) ([]byte, error) {
 	path := fmt.Sprintf("api/v2/edge/app/%d/download?version=%s", id, version)
-	req := c.newRequest(http.MethodGet, path, nil)
-	resp, err := c.c.Do(req)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -110,9 +131,13 @@ func (c *Client) DownloadEdgeApp(id int64, version string) ([]byte, error) {
 }
 
 func (c *Client) GetEdgeAppVersions(appID int64, untagged bool) ([]*EdgeAppVersion, error) {
+	return c.GetEdgeAppVersionsContext(context.Background(), appID, untagged)
+}
+
+func (c *Client) GetEdgeAppVersionsContext(ctx context.Context, appID int64, untagged bool) ([]*EdgeAppVersion, error) {
 	res := make([]*EdgeAppVersion, 0, 10)
 	path := fmt.Sprintf("api/v2/edge/app/%d/version?untagged=%t", appID, untagged)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -120,6 +145,10 @@ func (c *Client) GetEdgeAppVersions(appID int64, untagged bool) ([]*EdgeAppVersi
 }
 
 func (c *Client) CreateEdgeAppVersion(appID int64, luaFile, jsonFile io.Reader) (string, error) {
+	return c.CreateEdgeAppVersionContext(context.Background(), appID, luaFile, jsonFile)
+}
+
+func (c *Client) CreateEdgeAppVersionContext(ctx context.Context, appID int64, luaFile, jsonFile io.Reader) (string, error) {
 	res := &struct {
 		Hash string `json:"hash"`
 	}{}
@@ -145,7 +174,7 @@ func (c *Client) CreateEdgeAppVersion(appID int64, luaFile, jsonFile io.Reader)
 		}
 	}
 	w.Close()
-	req := c.newRequest(http.MethodPost, path, body)
+	req := c.newRequest(ctx, http.MethodPost, path, body)
 	req.Header.Set("Content-Type", w.FormDataContentType())
 	if err := c.do(req, res); err != nil {
 		return "", err
@@ -154,9 +183,13 @@ func (c *Client) CreateEdgeAppVersion(appID int64, luaFile, jsonFile io.Reader)
 }
 
 func (c *Client) NameEdgeAppVersion(appID int64, version *EdgeAppVersion) (*EdgeAppVersion, error) {
+	return c.NameEdgeAppVersionContext(context.Background(), appID, version)
+}
+
+func (c *Client) NameEdgeAppVersionContext(ctx context.Context, appID int64, version *EdgeAppVersion) (*EdgeAppVersion, error) {
 	res := &EdgeAppVersion{}
 	path := fmt.Sprintf("api/v2/edge/app/%d/publish", appID)
-	req := c.newRequest(http.MethodPost, path, requestBody(version))
+	req := c.newRequest(ctx, http.MethodPost, path, requestBody(version))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, res); err != nil {
 		return nil, err
@@ -165,9 +198,13 @@ func (c *Client) NameEdgeAppVersion(appID int64, version *EdgeAppVersion) (*Edge
 }
 
 func (c *Client) GetEdgeAppConfigOptions(appID int64, version string) (json.RawMessage, error) {
+	return c.GetEdgeAppConfigOptionsContext(context.Background(), appID, version)
+}
+
+func (c *Client) GetEdgeAppConfigOptionsContext(ctx context.Context, appID int64, version string) (json.RawMessage, error) {
 	path := fmt.Sprintf("api/v2/edge/app/%d/configure?version=%s", appID, version)
-	req := c.newRequest(http.MethodGet, path, nil)
-	resp, err := c.c.Do(req)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -184,9 +221,13 @@ func (c *Client) GetEdgeAppConfigOptions(appID int64, version string) (json.RawM
 }
 
 func (c *Client) GetConfiguredEdgeApps(installationID int64) ([]*EdgeAppConfig, error) {
+	return c.GetConfiguredEdgeAppsContext(context.Background(), installationID)
+}
+
+func (c *Client) GetConfiguredEdgeAppsContext(ctx context.Context, installationID int64) ([]*EdgeAppConfig, error) {
 	res := make([]*EdgeAppConfig, 0, 10)
 	path := fmt.Sprintf("api/v2/edge/app/configured/%d", installationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -194,9 +235,13 @@ func (c *Client) GetConfiguredEdgeApps(installationID int64) ([]*EdgeAppConfig,
 }
 
 func (c *Client) CreateEdgeAppInstance(config *EdgeAppConfig) (*EdgeAppConfig, error) {
+	return c.CreateEdgeAppInstanceContext(context.Background(), config)
+}
+
+func (c *Client) CreateEdgeAppInstanceContext(ctx context.Context, config *EdgeAppConfig) (*EdgeAppConfig, error) {
 	res := &EdgeAppConfig{}
 	path := fmt.Sprintf("api/v2/edge/app/configured/%d", config.InstallationID)
-	req := c.newRequest(http.MethodPost, path, requestBody(config))
+	req := c.newRequest(ctx, http.MethodPost, path, requestBody(config))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, res); err != nil {
 		return nil, err
@@ -205,9 +250,13 @@ func (c *Client) CreateEdgeAppInstance(config *EdgeAppConfig) (*EdgeAppConfig, e
 }
 
 func (c *Client) GetEdgeAppInstance(InstallationID, instanceID int64) (*EdgeAppConfig, error) {
+	return c.GetEdgeAppInstanceContext(context.Background(), InstallationID, instanceID)
+}
+
+func (c *Client) GetEdgeAppInstanceContext(ctx context.Context, InstallationID, instanceID int64) (*EdgeAppConfig, error) {
 	res := &EdgeAppConfig{}
 	path := fmt.Sprintf("api/v2/edge/app/configured/%d/%d", InstallationID, instanceID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
@@ -215,9 +264,13 @@ func (c *Client) GetEdgeAppInstance(InstallationID, instanceID int64) (*EdgeAppC
 }
 
 func (c *Client) UpdateEdgeAppInstance(config *EdgeAppConfig) (*EdgeAppConfig, error) {
+	return c.UpdateEdgeAppInstanceContext(context.Background(), config)
+}
+
+func (c *Client) UpdateEdgeAppInstanceContext(ctx context.Context, config *EdgeAppConfig) (*EdgeAppConfig, error) {
 	res := &EdgeAppConfig{}
 	path := fmt.Sprintf("api/v2/edge/app/configured/%d/%d", config.InstallationID, config.ID)
-	req := c.newRequest(http.MethodPut, path, requestBody(config))
+	req := c.newRequest(ctx, http.MethodPut, path, requestBody(config))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, res); err != nil {
 		return nil, err
@@ -226,7 +279,11 @@ func (c *Client) UpdateEdgeAppInstance(config *EdgeAppConfig) (*EdgeAppConfig, e
 }
 
 func (c *Client) DeleteEdgeAppInstance(config *EdgeAppConfig) error {
+	return c.DeleteEdgeAppInstanceContext(context.Background(), config)
+}
+
+func (c *Client) DeleteEdgeAppInstanceContext(ctx context.Context, config *EdgeAppConfig) error {
 	path := fmt.Sprintf("api/v2/edge/app/configured/%d/%d", config.InstallationID, config.ID)
-	req := c.newRequest(http.MethodDelete, path, nil)
+	req := c.newRequest(ctx, http.MethodDelete, path, nil)
 	return c.do(req, nil)
 }
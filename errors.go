@@ -0,0 +1,89 @@
+package lynx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+// Sentinel errors that an Error matches with errors.Is depending on its status code
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// maxErrorBody is the maximum number of bytes read from an error response
+const maxErrorBody = 1 << 16
+
+// maxErrorSnippet is the maximum number of bytes of the raw body kept in Error.Body
+const maxErrorSnippet = 512
+
+// Is makes errors.Is(err, ErrNotFound) and the other sentinels match on the status code.
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.Code == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.Code == http.StatusForbidden
+	case ErrConflict:
+		return e.Code == http.StatusConflict
+	case ErrRateLimited:
+		return e.Code == http.StatusTooManyRequests
+	}
+	return false
+}
+
+func requestError(response *http.Response) error {
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+	err := Error{
+		Code:      response.StatusCode,
+		RequestID: requestID(response.Header),
+	}
+	if response.Request != nil {
+		err.Method = response.Request.Method
+		err.Path = response.Request.URL.Path
+	}
+	if response.StatusCode == http.StatusRequestURITooLong || response.StatusCode == http.StatusNoContent {
+		return err
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 {
+		if json.Unmarshal(trimmed, &err) != nil {
+			err.Body = snippet(trimmed)
+		}
+	}
+	if err.Message == "" {
+		err.Message = http.StatusText(response.StatusCode)
+	}
+	return err
+}
+
+func requestID(h http.Header) string {
+	for _, key := range []string{"X-Request-Id", "X-Correlation-Id", "X-Trace-Id"} {
+		if v := h.Get(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func snippet(body []byte) string {
+	if len(body) <= maxErrorSnippet {
+		return string(body)
+	}
+	body = body[:maxErrorSnippet]
+	for len(body) > 0 && !utf8.Valid(body) {
+		body = body[:len(body)-1]
+	}
+	return string(body) + "..."
+}
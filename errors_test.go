@@ -0,0 +1,92 @@
+package lynx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantMessage string
+		wantBody    string
+		sentinel    error
+	}{
+		{
+			name:        "JSON",
+			status:      http.StatusNotFound,
+			body:        `{"message":"function not found"}`,
+			wantMessage: "function not found",
+			sentinel:    ErrNotFound,
+		},
+		{
+			name:        "HTML",
+			status:      http.StatusBadGateway,
+			body:        "<html>bad gateway</html>",
+			wantMessage: "Bad Gateway",
+			wantBody:    "<html>bad gateway</html>",
+		},
+		{
+			name:        "Empty",
+			status:      http.StatusTooManyRequests,
+			wantMessage: "Too Many Requests",
+			sentinel:    ErrRateLimited,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-ID", "req-1")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			c := NewClient(&Options{Authenticator: AuthNone{}, APIBase: srv.URL})
+			_, err := c.GetFunction(1, 2)
+			apiErr := Error{}
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetFunction() error = %v, want Error", err)
+			}
+			if apiErr.Code != tt.status || apiErr.Message != tt.wantMessage || apiErr.Body != tt.wantBody {
+				t.Errorf("GetFunction() error = %+v", apiErr)
+			}
+			if apiErr.Method != http.MethodGet || apiErr.Path != "/api/v2/functionx/1/2" || apiErr.RequestID != "req-1" {
+				t.Errorf("GetFunction() error request info = %+v", apiErr)
+			}
+			if !strings.Contains(err.Error(), "GET /api/v2/functionx/1/2, request id req-1") ||
+				!strings.Contains(err.Error(), tt.wantBody) {
+				t.Errorf("Error() = %q, want request info and body", err.Error())
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.sentinel)
+			}
+			if errors.Is(err, ErrConflict) {
+				t.Errorf("errors.Is(%v, ErrConflict) = true", err)
+			}
+		})
+	}
+}
+
+func TestError_Comparable(t *testing.T) {
+	fields := FieldErrors{"name": "required"}
+	var a, b error = Error{Code: 400, Fields: &fields}, Error{Code: 400, Fields: &fields}
+	if a != b {
+		t.Errorf("errors with the same fields are not equal")
+	}
+	if a == error(Error{Code: 400}) {
+		t.Errorf("errors with different fields are equal")
+	}
+	e := Error{}
+	if err := json.Unmarshal([]byte(`{"message":"invalid","fields":{"name":"required"}}`), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Fields == nil || (*e.Fields)["name"] != "required" {
+		t.Errorf("Fields = %v", e.Fields)
+	}
+}
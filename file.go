@@ -2,6 +2,7 @@ package lynx
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -25,9 +26,13 @@ type File struct {
 var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 
 func (c *Client) GetFilesInstallation(installationID int64) ([]*File, error) {
+	return c.GetFilesInstallationContext(context.Background(), installationID)
+}
+
+func (c *Client) GetFilesInstallationContext(ctx context.Context, installationID int64) ([]*File, error) {
 	res := make([]*File, 0, 10)
 	path := fmt.Sprintf("api/v2/file/installation/%d", installationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -35,9 +40,13 @@ func (c *Client) GetFilesInstallation(installationID int64) ([]*File, error) {
 }
 
 func (c *Client) GetFileInstallation(installationID, fileID int64) (*File, error) {
+	return c.GetFileInstallationContext(context.Background(), installationID, fileID)
+}
+
+func (c *Client) GetFileInstallationContext(ctx context.Context, installationID, fileID int64) (*File, error) {
 	res := &File{}
 	path := fmt.Sprintf("api/v2/file/installation/%d/%d", installationID, fileID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
@@ -45,9 +54,13 @@ func (c *Client) GetFileInstallation(installationID, fileID int64) (*File, error
 }
 
 func (c *Client) GetFilesOrganization(organizationID int64) ([]*File, error) {
+	return c.GetFilesOrganizationContext(context.Background(), organizationID)
+}
+
+func (c *Client) GetFilesOrganizationContext(ctx context.Context, organizationID int64) ([]*File, error) {
 	res := make([]*File, 0, 10)
 	path := fmt.Sprintf("api/v2/file/organization/%d", organizationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -55,9 +68,13 @@ func (c *Client) GetFilesOrganization(organizationID int64) ([]*File, error) {
 }
 
 func (c *Client) GetFileOrganization(organizationID, fileID int64) (*File, error) {
+	return c.GetFileOrganizationContext(context.Background(), organizationID, fileID)
+}
+
+func (c *Client) GetFileOrganizationContext(ctx context.Context, organizationID, fileID int64) (*File, error) {
 	res := &File{}
 	path := fmt.Sprintf("api/v2/file/organization/%d/%d", organizationID, fileID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
@@ -65,6 +82,10 @@ func (c *Client) GetFileOrganization(organizationID, fileID int64) (*File, error
 }
 
 func (c *Client) CreateFileInstallation(installationID int64, fileName, mime string, r io.Reader) (*File, error) {
+	return c.CreateFileInstallationContext(context.Background(), installationID, fileName, mime, r)
+}
+
+func (c *Client) CreateFileInstallationContext(ctx context.Context, installationID int64, fileName, mime string, r io.Reader) (*File, error) {
 	path := fmt.Sprintf("api/v2/file/installation/%d", installationID)
 	res := make([]*File, 0, 1)
 	buf := new(bytes.Buffer)
@@ -88,7 +109,7 @@ func (c *Client) CreateFileInstallation(installationID int64, fileName, mime str
 		return nil, err
 	}
 
-	req := c.newRequest(http.MethodPost, path, buf)
+	req := c.newRequest(ctx, http.MethodPost, path, buf)
 	req.Header.Set("Content-Type", fmt.Sprintf("multipart/form-data; boundary=%s", w.Boundary()))
 	if err := c.do(req, &res); err != nil {
 		return nil, err
@@ -101,6 +122,10 @@ func (c *Client) CreateFileInstallation(installationID int64, fileName, mime str
 }
 
 func (c *Client) CreateFileOrganization(organizationID int64, fileName, mime string, r io.Reader) (*File, error) {
+	return c.CreateFileOrganizationContext(context.Background(), organizationID, fileName, mime, r)
+}
+
+func (c *Client) CreateFileOrganizationContext(ctx context.Context, organizationID int64, fileName, mime string, r io.Reader) (*File, error) {
 	path := fmt.Sprintf("api/v2/file/organization/%d", organizationID)
 	res := make([]*File, 0, 1)
 	buf := new(bytes.Buffer)
@@ -124,7 +149,7 @@ func (c *Client) CreateFileOrganization(organizationID int64, fileName, mime str
 		return nil, err
 	}
 
-	req := c.newRequest(http.MethodPost, path, buf)
+	req := c.newRequest(ctx, http.MethodPost, path, buf)
 	req.Header.Set("Content-Type", fmt.Sprintf("multipart/form-data; boundary=%s", w.Boundary()))
 	if err := c.do(req, &res); err != nil {
 		return nil, err
@@ -137,6 +162,10 @@ func (c *Client) CreateFileOrganization(organizationID int64, fileName, mime str
 }
 
 func (c *Client) UpdateFileInstallation(installationID, fileID int64, fileName, mime string, r io.Reader) (*File, error) {
+	return c.UpdateFileInstallationContext(context.Background(), installationID, fileID, fileName, mime, r)
+}
+
+func (c *Client) UpdateFileInstallationContext(ctx context.Context, installationID, fileID int64, fileName, mime string, r io.Reader) (*File, error) {
 	path := fmt.Sprintf("api/v2/file/installation/%d/%d", installationID, fileID)
 	res := &File{}
 	buf := new(bytes.Buffer)
@@ -160,7 +189,7 @@ func (c *Client) UpdateFileInstallation(installationID, fileID int64, fileName,
 		return nil, err
 	}
 
-	req := c.newRequest(http.MethodPost, path, buf)
+	req := c.newRequest(ctx, http.MethodPost, path, buf)
 	req.Header.Set("Content-Type", fmt.Sprintf("multipart/form-data; boundary=%s", w.Boundary()))
 	if err := c.do(req, res); err != nil {
 		return nil, err
@@ -170,6 +199,10 @@ func (c *Client) UpdateFileInstallation(installationID, fileID int64, fileName,
 }
 
 func (c *Client) UpdateFileOrganization(organizationID, fileID int64, fileName, mime string, r io.Reader) (*File, error) {
+	return c.UpdateFileOrganizationContext(context.Background(), organizationID, fileID, fileName, mime, r)
+}
+
+func (c *Client) UpdateFileOrganizationContext(ctx context.Context, organizationID, fileID int64, fileName, mime string, r io.Reader) (*File, error) {
 	path := fmt.Sprintf("api/v2/file/organization/%d/%d", organizationID, fileID)
 	res := &File{}
 	buf := new(bytes.Buffer)
@@ -193,7 +226,7 @@ func (c *Client) UpdateFileOrganization(organizationID, fileID int64, fileName,
 		return nil, err
 	}
 
-	req := c.newRequest(http.MethodPost, path, buf)
+	req := c.newRequest(ctx, http.MethodPost, path, buf)
 	req.Header.Set("Content-Type", fmt.Sprintf("multipart/form-data; boundary=%s", w.Boundary()))
 	if err := c.do(req, res); err != nil {
 		return nil, err
@@ -203,8 +236,12 @@ func (c *Client) UpdateFileOrganization(organizationID, fileID int64, fileName,
 }
 
 func (c *Client) DeleteFileInstallation(installationID, fileID int64) error {
+	return c.DeleteFileInstallationContext(context.Background(), installationID, fileID)
+}
+
+func (c *Client) DeleteFileInstallationContext(ctx context.Context, installationID, fileID int64) error {
 	path := fmt.Sprintf("api/v2/file/installation/%d/%d", installationID, fileID)
-	req := c.newRequest(http.MethodDelete, path, nil)
+	req := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(req, nil); err != nil {
 		return err
 	}
@@ -212,8 +249,12 @@ func (c *Client) DeleteFileInstallation(installationID, fileID int64) error {
 }
 
 func (c *Client) DeleteFileOrganization(organizationID, fileID int64) error {
+	return c.DeleteFileOrganizationContext(context.Background(), organizationID, fileID)
+}
+
+func (c *Client) DeleteFileOrganizationContext(ctx context.Context, organizationID, fileID int64) error {
 	path := fmt.Sprintf("api/v2/file/organization/%d/%d", organizationID, fileID)
-	req := c.newRequest(http.MethodDelete, path, nil)
+	req := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(req, nil); err != nil {
 		return err
 	}
@@ -221,13 +262,18 @@ func (c *Client) DeleteFileOrganization(organizationID, fileID int64) error {
 }
 
 func (c *Client) DownloadFile(hash string) (io.ReadCloser, error) {
+	return c.DownloadFileContext(context.Background(), hash)
+}
+
+func (c *Client) DownloadFileContext(ctx context.Context, hash string) (io.ReadCloser, error) {
 	path := fmt.Sprintf("api/v2/file/download/%s", hash)
-	req := c.newRequest(http.MethodGet, path, nil)
-	response, err := c.c.Do(req)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
+	response, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 	if err := requestError(response); err != nil {
+		response.Body.Close()
 		return nil, err
 	}
 
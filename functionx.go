@@ -1,6 +1,7 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -126,9 +127,13 @@ func (f FunctionList) MapByList(key string) map[string]FunctionList {
 }
 
 func (c *Client) GetFunctions(installationID int64, filter Filter) (FunctionList, error) {
+	return c.GetFunctionsContext(context.Background(), installationID, filter)
+}
+
+func (c *Client) GetFunctionsContext(ctx context.Context, installationID int64, filter Filter) (FunctionList, error) {
 	res := make([]*Function, 0, 20)
 	query := filter.ToURLValues()
-	request := c.newRequest(http.MethodGet, fmt.Sprintf("api/v2/functionx/%d?%s", installationID, query.Encode()), nil)
+	request := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("api/v2/functionx/%d?%s", installationID, query.Encode()), nil)
 	if err := c.do(request, &res); err != nil {
 		return nil, err
 	}
@@ -136,9 +141,13 @@ func (c *Client) GetFunctions(installationID int64, filter Filter) (FunctionList
 }
 
 func (c *Client) GetFunction(installationID, functionID int64) (*Function, error) {
+	return c.GetFunctionContext(context.Background(), installationID, functionID)
+}
+
+func (c *Client) GetFunctionContext(ctx context.Context, installationID, functionID int64) (*Function, error) {
 	function := &Function{}
 	path := fmt.Sprintf("api/v2/functionx/%d/%d", installationID, functionID)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, function); err != nil {
 		return nil, err
 	}
@@ -146,9 +155,13 @@ func (c *Client) GetFunction(installationID, functionID int64) (*Function, error
 }
 
 func (c *Client) CreateFunction(fn *Function) (*Function, error) {
+	return c.CreateFunctionContext(context.Background(), fn)
+}
+
+func (c *Client) CreateFunctionContext(ctx context.Context, fn *Function) (*Function, error) {
 	function := &Function{}
 	path := fmt.Sprintf("api/v2/functionx/%d", fn.InstallationID)
-	request := c.newRequest(http.MethodPost, path, requestBody(fn))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(fn))
 	if err := c.do(request, function); err != nil {
 		return nil, err
 	}
@@ -156,8 +169,12 @@ func (c *Client) CreateFunction(fn *Function) (*Function, error) {
 }
 
 func (c *Client) DeleteFunction(fn *Function) error {
+	return c.DeleteFunctionContext(context.Background(), fn)
+}
+
+func (c *Client) DeleteFunctionContext(ctx context.Context, fn *Function) error {
 	path := fmt.Sprintf("api/v2/functionx/%d/%d", fn.InstallationID, fn.ID)
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
@@ -165,9 +182,13 @@ func (c *Client) DeleteFunction(fn *Function) error {
 }
 
 func (c *Client) UpdateFunction(fn *Function) (*Function, error) {
+	return c.UpdateFunctionContext(context.Background(), fn)
+}
+
+func (c *Client) UpdateFunctionContext(ctx context.Context, fn *Function) (*Function, error) {
 	function := &Function{}
 	path := fmt.Sprintf("api/v2/functionx/%d/%d", fn.InstallationID, fn.ID)
-	request := c.newRequest(http.MethodPut, path, requestBody(fn))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(fn))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, function); err != nil {
 		return nil, err
@@ -176,9 +197,13 @@ func (c *Client) UpdateFunction(fn *Function) (*Function, error) {
 }
 
 func (c *Client) GetFunctionMeta(installationID, functionID int64, key string) (*MetaObject, error) {
+	return c.GetFunctionMetaContext(context.Background(), installationID, functionID, key)
+}
+
+func (c *Client) GetFunctionMetaContext(ctx context.Context, installationID, functionID int64, key string) (*MetaObject, error) {
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/functionx/%d/%d/meta/%s", installationID, functionID, key)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, mo); err != nil {
 		return nil, err
 	}
@@ -186,12 +211,16 @@ func (c *Client) GetFunctionMeta(installationID, functionID int64, key string) (
 }
 
 func (c *Client) CreateFunctionMeta(installationID, functionID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
+	return c.CreateFunctionMetaContext(context.Background(), installationID, functionID, key, meta, silent)
+}
+
+func (c *Client) CreateFunctionMetaContext(ctx context.Context, installationID, functionID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/functionx/%d/%d/meta/%s?%s", installationID, functionID, key, query.Encode())
-	request := c.newRequest(http.MethodPost, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -200,13 +229,17 @@ func (c *Client) CreateFunctionMeta(installationID, functionID int64, key string
 }
 
 func (c *Client) UpdateFunctionMeta(installationID, functionID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
+	return c.UpdateFunctionMetaContext(context.Background(), installationID, functionID, key, meta, silent, createMissing)
+}
+
+func (c *Client) UpdateFunctionMetaContext(ctx context.Context, installationID, functionID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent":         []string{fmt.Sprintf("%t", silent)},
 		"create_missing": []string{fmt.Sprintf("%t", createMissing)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/functionx/%d/%d/meta/%s?%s", installationID, functionID, key, query.Encode())
-	request := c.newRequest(http.MethodPut, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -215,11 +248,15 @@ func (c *Client) UpdateFunctionMeta(installationID, functionID int64, key string
 }
 
 func (c *Client) DeleteFunctionMeta(installationID, functionID int64, key string, silent bool) error {
+	return c.DeleteFunctionMetaContext(context.Background(), installationID, functionID, key, silent)
+}
+
+func (c *Client) DeleteFunctionMetaContext(ctx context.Context, installationID, functionID int64, key string, silent bool) error {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	path := fmt.Sprintf("api/v2/functionx/%d/%d/meta/%s?%s", installationID, functionID, key, query.Encode())
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
@@ -0,0 +1,82 @@
+package lynx
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Topics returns the topics of the function from its topic_<key> meta, keyed by <key>.
+// The topics are relative to the installation, without the client id prefix.
+func (f *Function) Topics() map[string]string {
+	res := make(map[string]string, 2)
+	for k, v := range f.Meta {
+		if key, found := strings.CutPrefix(k, "topic_"); found && v != "" {
+			res[key] = v
+		}
+	}
+	return res
+}
+
+// Topic returns the topic_<key> meta of the function. An empty key means "read".
+func (f *Function) Topic(key string) (string, bool) {
+	if key == "" {
+		key = "read"
+	}
+	topic, ok := f.Meta["topic_"+key]
+	return topic, ok && topic != ""
+}
+
+// installationClientID returns the client id of the installation, which prefixes all its MQTT topics.
+// Client ids are cached since they never change for an installation.
+func (c *Client) installationClientID(ctx context.Context, installationID int64) (int64, error) {
+	c.clientIDMu.Lock()
+	clientID, ok := c.clientIDs[installationID]
+	c.clientIDMu.Unlock()
+	if ok {
+		return clientID, nil
+	}
+	installation, err := c.GetInstallationRowContext(ctx, installationID)
+	if err != nil {
+		return 0, err
+	}
+	c.clientIDMu.Lock()
+	c.clientIDs[installationID] = installation.ClientID
+	c.clientIDMu.Unlock()
+	return installation.ClientID, nil
+}
+
+// functionTopic returns the full MQTT topic for the topic_<key> meta of the function.
+// The meta is checked before the client id is looked up, to not request the installation in vain.
+func (c *Client) functionTopic(fn *Function, key string) (Topic, error) {
+	if _, ok := fn.Topic(key); !ok {
+		return FunctionTopic(0, fn, key)
+	}
+	clientID, err := c.installationClientID(context.Background(), fn.InstallationID)
+	if err != nil {
+		return "", err
+	}
+	return FunctionTopic(clientID, fn, key)
+}
+
+// SetFunctionValue publishes value to the topic_set topic of the function.
+func (c *Client) SetFunctionValue(fn *Function, value float64) error {
+	topic, err := c.functionTopic(fn, "set")
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return c.Publish(topic.String(), Message{
+		Value:     value,
+		Timestamp: float64(now.UnixNano()) / float64(time.Second),
+	}, 0)
+}
+
+// SubscribeFunction subscribes with QoS 0 to the topic_<key> topic of the function, an empty key means "read".
+func (c *Client) SubscribeFunction(fn *Function, key string, handler MessageHandler) error {
+	topic, err := c.functionTopic(fn, key)
+	if err != nil {
+		return err
+	}
+	return c.Subscribe(topic.String(), 0, handler)
+}
@@ -0,0 +1,54 @@
+package lynx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+)
+
+func TestClient_SetFunctionValue(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	b := lynxtest.NewTestBroker(t)
+
+	installation := srv.AddInstallation(&lynx.InstallationRow{ClientID: 1234})
+	fn := srv.AddFunction(&lynx.Function{
+		InstallationID: installation.ID,
+		Meta: lynx.Meta{
+			"topic_read": "obj/switch/1/state",
+			"topic_set":  "set/obj/switch/1/state",
+		},
+	})
+	c := b.NewTestClient(t, "test", &lynx.Options{APIBase: srv.URL})
+
+	if got := fn.Topics(); len(got) != 2 || got["set"] != "set/obj/switch/1/state" {
+		t.Errorf("Topics() = %v", got)
+	}
+	received := make(chan lynx.Message, 1)
+	if err := c.SubscribeFunction(fn, "", func(topic string, msg lynx.Message) {
+		received <- msg
+	}); err != nil {
+		t.Fatalf("SubscribeFunction() error = %v", err)
+	}
+	if err := c.SetFunctionValue(fn, 1); err != nil {
+		t.Fatalf("SetFunctionValue() error = %v", err)
+	}
+	p, ok := b.WaitForPublish("1234/set/obj/switch/1/state", time.Second)
+	if msg, _ := p.Message(); !ok || msg.Value != 1 {
+		t.Errorf("SetFunctionValue() published %+v", p)
+	}
+	b.InjectMessage("1234/obj/switch/1/state", lynx.Message{Value: 1})
+	select {
+	case msg := <-received:
+		if msg.Value != 1 {
+			t.Errorf("SubscribeFunction() received %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("SubscribeFunction() no message received")
+	}
+	if err := c.SubscribeFunction(fn, "missing", func(string, lynx.Message) {}); err == nil {
+		t.Errorf("SubscribeFunction() with missing topic expected error")
+	}
+}
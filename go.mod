@@ -4,6 +4,7 @@ go 1.24
 
 require (
 	github.com/eclipse/paho.mqtt.golang v1.5.0
+	github.com/spf13/cast v1.9.2
 	github.com/spf13/viper v1.20.1
 	golang.org/x/net v0.41.0
 )
@@ -20,7 +21,6 @@ require (
 	github.com/sagikazarmark/slog-shim v0.1.0 // indirect
 	github.com/sourcegraph/conc v0.3.0 // indirect
 	github.com/spf13/afero v1.14.0 // indirect
-	github.com/spf13/cast v1.9.2 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
 	github.com/subosito/gotenv v1.6.0 // indirect
 	go.uber.org/atomic v1.11.0 // indirect
@@ -1,6 +1,7 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -29,9 +30,13 @@ type InstallationRow struct {
 }
 
 func (c *Client) GetInstallationRow(installationID int64) (*InstallationRow, error) {
+	return c.GetInstallationRowContext(context.Background(), installationID)
+}
+
+func (c *Client) GetInstallationRowContext(ctx context.Context, installationID int64) (*InstallationRow, error) {
 	res := &InstallationRow{}
 	path := fmt.Sprintf("api/v2/installation/%d", installationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
@@ -39,9 +44,13 @@ func (c *Client) GetInstallationRow(installationID int64) (*InstallationRow, err
 }
 
 func (c *Client) UpdateInstallation(i *InstallationRow) (*InstallationRow, error) {
+	return c.UpdateInstallationContext(context.Background(), i)
+}
+
+func (c *Client) UpdateInstallationContext(ctx context.Context, i *InstallationRow) (*InstallationRow, error) {
 	res := &InstallationRow{}
 	path := fmt.Sprintf("api/v2/installation/%d", i.ID)
-	request := c.newRequest(http.MethodPut, path, requestBody(i))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(i))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, res); err != nil {
 		return nil, err
@@ -50,9 +59,13 @@ func (c *Client) UpdateInstallation(i *InstallationRow) (*InstallationRow, error
 }
 
 func (c *Client) ListInstallations(filter Filter) ([]*InstallationRow, error) {
+	return c.ListInstallationsContext(context.Background(), filter)
+}
+
+func (c *Client) ListInstallationsContext(ctx context.Context, filter Filter) ([]*InstallationRow, error) {
 	res := make([]*InstallationRow, 0, 20)
 	query := filter.ToURLValues()
-	request := c.newRequest(http.MethodGet, fmt.Sprintf("api/v2/installation?%s", query.Encode()), nil)
+	request := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("api/v2/installation?%s", query.Encode()), nil)
 	if err := c.do(request, &res); err != nil {
 		return nil, err
 	}
@@ -60,11 +73,15 @@ func (c *Client) ListInstallations(filter Filter) ([]*InstallationRow, error) {
 }
 
 func (c *Client) GetInstallations(assignedOnly bool) ([]*Installation, error) {
+	return c.GetInstallationsContext(context.Background(), assignedOnly)
+}
+
+func (c *Client) GetInstallationsContext(ctx context.Context, assignedOnly bool) ([]*Installation, error) {
 	res := make([]*Installation, 0, 20)
 	query := url.Values{}
 	query["assigned"] = []string{fmt.Sprintf("%v", assignedOnly)}
 	path := fmt.Sprintf("%s?%s", "api/v2/installationinfo", query.Encode())
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, &res); err != nil {
 		return nil, err
 	}
@@ -72,8 +89,12 @@ func (c *Client) GetInstallations(assignedOnly bool) ([]*Installation, error) {
 }
 
 func (c *Client) GetInstallation(installationID int64) (*Installation, error) {
+	return c.GetInstallationContext(context.Background(), installationID)
+}
+
+func (c *Client) GetInstallationContext(ctx context.Context, installationID int64) (*Installation, error) {
 	res := make([]*Installation, 0, 20)
-	request := c.newRequest(http.MethodGet, "api/v2/installationinfo?assigned=false", nil)
+	request := c.newRequest(ctx, http.MethodGet, "api/v2/installationinfo?assigned=false", nil)
 	if err := c.do(request, &res); err != nil {
 		return nil, err
 	}
@@ -89,11 +110,15 @@ func (c *Client) GetInstallation(installationID int64) (*Installation, error) {
 }
 
 func (c *Client) GetInstallationByClientID(clientID int64, assignedOnly bool) (*Installation, error) {
+	return c.GetInstallationByClientIDContext(context.Background(), clientID, assignedOnly)
+}
+
+func (c *Client) GetInstallationByClientIDContext(ctx context.Context, clientID int64, assignedOnly bool) (*Installation, error) {
 	res := &Installation{}
 	query := url.Values{}
 	query["assigned"] = []string{fmt.Sprintf("%v", assignedOnly)}
 	path := fmt.Sprintf("api/v2/installationinfo/%d?%s", clientID, query.Encode())
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
@@ -101,9 +126,13 @@ func (c *Client) GetInstallationByClientID(clientID int64, assignedOnly bool) (*
 }
 
 func (c *Client) GetInstallationMeta(installationID int64, key string) (*MetaObject, error) {
+	return c.GetInstallationMetaContext(context.Background(), installationID, key)
+}
+
+func (c *Client) GetInstallationMetaContext(ctx context.Context, installationID int64, key string) (*MetaObject, error) {
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/installation/%d/meta/%s", installationID, key)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, mo); err != nil {
 		return nil, err
 	}
@@ -111,12 +140,16 @@ func (c *Client) GetInstallationMeta(installationID int64, key string) (*MetaObj
 }
 
 func (c *Client) CreateInstallationMeta(installationID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
+	return c.CreateInstallationMetaContext(context.Background(), installationID, key, meta, silent)
+}
+
+func (c *Client) CreateInstallationMetaContext(ctx context.Context, installationID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/installation/%d/meta/%s?%s", installationID, key, query.Encode())
-	request := c.newRequest(http.MethodPost, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -125,13 +158,17 @@ func (c *Client) CreateInstallationMeta(installationID int64, key string, meta M
 }
 
 func (c *Client) UpdateInstallationMeta(installationID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
+	return c.UpdateInstallationMetaContext(context.Background(), installationID, key, meta, silent, createMissing)
+}
+
+func (c *Client) UpdateInstallationMetaContext(ctx context.Context, installationID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent":         []string{fmt.Sprintf("%t", silent)},
 		"create_missing": []string{fmt.Sprintf("%t", createMissing)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/installation/%d/meta/%s?%s", installationID, key, query.Encode())
-	request := c.newRequest(http.MethodPut, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -140,11 +177,15 @@ func (c *Client) UpdateInstallationMeta(installationID int64, key string, meta M
 }
 
 func (c *Client) DeleteInstallationMeta(installationID int64, key string, silent bool) error {
+	return c.DeleteInstallationMetaContext(context.Background(), installationID, key, silent)
+}
+
+func (c *Client) DeleteInstallationMetaContext(ctx context.Context, installationID int64, key string, silent bool) error {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	path := fmt.Sprintf("api/v2/installation/%d/meta/%s?%s", installationID, key, query.Encode())
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
@@ -1,8 +1,10 @@
 package lynx
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"math"
 	"net/http"
 	"net/url"
@@ -58,18 +60,22 @@ func (s Status) Map() map[string]*LogEntry {
 	return res
 }
 func (c *Client) Status(installationID int64, topicFilter []string) (Status, error) {
+	return c.StatusContext(context.Background(), installationID, topicFilter)
+}
+
+func (c *Client) StatusContext(ctx context.Context, installationID int64, topicFilter []string) (Status, error) {
 	status := Status{}
 	query := url.Values{
 		"topics": topicFilter,
 	}
 	path := fmt.Sprintf("api/v2/status/%d?%s", installationID, query.Encode())
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	err := c.do(req, &status)
 	getErr := Error{}
 	if errors.As(err, &getErr) && getErr.Code == http.StatusRequestURITooLong {
 		path = fmt.Sprintf("api/v2/status/%d", installationID)
 		body := requestBody(topicFilter)
-		req = c.newRequest(http.MethodPost, path, body)
+		req = c.newRequest(ctx, http.MethodPost, path, body)
 		if postErr := c.do(req, &status); postErr != nil {
 			newErr := Error{}
 			ok := errors.As(postErr, &newErr)
@@ -84,19 +90,28 @@ func (c *Client) Status(installationID int64, topicFilter []string) (Status, err
 	return status, err
 }
 
+func defaultLogOptionsV3() *LogOptionsV3 {
+	t := time.Now()
+	return &LogOptionsV3{
+		From:        t.Add(-time.Hour * 24),
+		To:          t,
+		Limit:       500,
+		Offset:      0,
+		Order:       LogOrderDesc,
+		TopicFilter: []string{},
+	}
+}
+
 // Log returns log entries in the V3 format. If opts is nil some default values will be used.
 func (c *V3Client) Log(installationID int64, opts *LogOptionsV3) (*V3Log, error) {
+	return c.LogContext(context.Background(), installationID, opts)
+}
+
+// LogContext is like Log but uses ctx for the underlying requests.
+func (c *V3Client) LogContext(ctx context.Context, installationID int64, opts *LogOptionsV3) (*V3Log, error) {
 	log := &V3Log{}
 	if opts == nil {
-		t := time.Now()
-		opts = &LogOptionsV3{
-			From:        t.Add(-time.Hour * 24),
-			To:          t,
-			Limit:       500,
-			Offset:      0,
-			Order:       LogOrderDesc,
-			TopicFilter: []string{},
-		}
+		opts = defaultLogOptionsV3()
 	}
 	query := url.Values{
 		"from":          []string{fmt.Sprintf("%d", opts.From.Unix())},
@@ -110,14 +125,14 @@ func (c *V3Client) Log(installationID int64, opts *LogOptionsV3) (*V3Log, error)
 	}
 
 	path := fmt.Sprintf("api/v3beta/log/%d?%s", installationID, query.Encode())
-	req := c.c.newRequest(http.MethodGet, path, nil)
+	req := c.c.newRequest(ctx, http.MethodGet, path, nil)
 	err := c.c.do(req, log)
 	getErr := Error{}
 	if errors.As(err, &getErr) && getErr.Code == http.StatusRequestURITooLong {
 		delete(query, "topics")
 		path = fmt.Sprintf("api/v3beta/log/%d?%s", installationID, query.Encode())
 		body := requestBody(opts.TopicFilter)
-		req = c.c.newRequest(http.MethodPost, path, body)
+		req = c.c.newRequest(ctx, http.MethodPost, path, body)
 		if postErr := c.c.do(req, log); postErr != nil {
 			newErr := Error{}
 			ok := errors.As(postErr, &newErr)
@@ -131,3 +146,33 @@ func (c *V3Client) Log(installationID int64, opts *LogOptionsV3) (*V3Log, error)
 
 	return log, err
 }
+
+// LogIter returns an iterator over all log entries matching opts, starting at opts.Offset.
+// Pages of opts.Limit entries, 500 if Limit is not set, are fetched as the iterator advances
+// until Total entries have been read. A zero To is set to the time the iterator is created.
+// Iteration stops after the first error. If opts is nil the same defaults as for Log are used.
+//
+// Pages are requested by offset, so entries logged within the From and To window while
+// iterating shift the following pages. With LogOrderDesc this repeats entries at page
+// boundaries, set To to a time in the past to iterate over a stable window.
+func (c *V3Client) LogIter(ctx context.Context, installationID int64, opts *LogOptionsV3) iter.Seq2[LogEntry, error] {
+	if opts == nil {
+		opts = defaultLogOptionsV3()
+	}
+	iterOpts := *opts
+	if iterOpts.Limit <= 0 {
+		iterOpts.Limit = 500
+	}
+	if iterOpts.To.IsZero() {
+		iterOpts.To = time.Now()
+	}
+	return paginate(ctx, iterOpts.Offset, func(ctx context.Context, offset int64) ([]LogEntry, int64, error) {
+		pageOpts := iterOpts
+		pageOpts.Offset = offset
+		page, err := c.LogContext(ctx, installationID, &pageOpts)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Data, page.Total, nil
+	})
+}
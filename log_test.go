@@ -0,0 +1,64 @@
+package lynx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestV3Client_LogIter(t *testing.T) {
+	const total = 7
+	requests, values := 0, []float64{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		res := V3Log{Total: total}
+		for i := offset; i < total && i < offset+limit; i++ {
+			res.Data = append(res.Data, LogEntry{Topic: "obj/test", Value: float64(i)})
+		}
+		res.Count = len(res.Data)
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	c := NewClient(&Options{Authenticator: AuthNone{}, APIBase: srv.URL})
+	opts := &LogOptionsV3{
+		Limit: 3,
+		From:  time.Unix(0, 0),
+		To:    time.Now(),
+		Order: LogOrderAsc,
+	}
+	for entry, err := range c.V3().LogIter(t.Context(), 1, opts) {
+		if err != nil {
+			t.Fatalf("LogIter() error = %v", err)
+		}
+		values = append(values, entry.Value)
+	}
+	if len(values) != total || values[total-1] != total-1 || requests != 3 {
+		t.Errorf("LogIter() = %v in %d requests", values, requests)
+	}
+
+	requests = 0
+	for entry := range c.V3().LogIter(t.Context(), 1, opts) {
+		if entry.Value == 1 {
+			break
+		}
+	}
+	if requests != 1 {
+		t.Errorf("LogIter() with break made %d requests, want 1", requests)
+	}
+	requests, values = 0, nil
+	for entry, err := range c.V3().LogIter(t.Context(), 1, &LogOptionsV3{From: time.Unix(0, 0), Order: LogOrderAsc}) {
+		if err != nil {
+			t.Fatalf("LogIter() error = %v", err)
+		}
+		values = append(values, entry.Value)
+	}
+	if len(values) != total || requests != 1 {
+		t.Errorf("LogIter() without Limit = %v in %d requests", values, requests)
+	}
+}
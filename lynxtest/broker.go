@@ -0,0 +1,446 @@
+package lynxtest
+
+import (
+	"encoding/json"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/spf13/viper"
+)
+
+// Publish is a message published to the Broker
+type Publish struct {
+	ClientID string
+	Topic    string
+	QoS      byte
+	Retained bool
+	Payload  []byte
+}
+
+// Message decodes the payload as a Lynx message.
+func (p Publish) Message() (lynx.Message, error) {
+	msg := lynx.Message{}
+	err := json.Unmarshal(p.Payload, &msg)
+	return msg, err
+}
+
+// Broker is a minimal in-process MQTT 3.1.1 broker for tests. It supports QoS 0 and 1
+// subscriptions, QoS 0-2 publishing, retained messages, wildcards and will messages.
+// Sessions are not persisted, every connection starts with a clean session.
+type Broker struct {
+	// Authenticate is called for every connection if set, returning false refuses the connection.
+	// It must be set before any client connects.
+	Authenticate func(clientID, username, password string) bool
+
+	listener  net.Listener
+	mu        sync.Mutex
+	cond      *sync.Cond
+	conns     map[*brokerConn]bool
+	retained  map[string]Publish
+	published []Publish
+	// acked is the last message received per client and message id, to detect redeliveries
+	acked  map[string]map[uint16]Publish
+	closed bool
+}
+
+type brokerConn struct {
+	net.Conn
+	clientID string
+	wmu      sync.Mutex
+	nextID   uint16
+	subs     map[string]byte
+	will     *Publish
+}
+
+// NewBroker starts a broker listening on a random localhost port. The caller should call Close when finished.
+func NewBroker() (*Broker, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{
+		listener: l,
+		conns:    make(map[*brokerConn]bool),
+		retained: make(map[string]Publish),
+		acked:    make(map[string]map[uint16]Publish),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	go b.accept()
+	return b, nil
+}
+
+// NewTestBroker starts a broker like NewBroker which is closed when the test finishes.
+func NewTestBroker(t testing.TB) *Broker {
+	t.Helper()
+	b, err := NewBroker()
+	if err != nil {
+		t.Fatalf("NewBroker() error = %v", err)
+	}
+	t.Cleanup(b.Close)
+	return b
+}
+
+// NewTestClient creates a client connected to the broker which is disconnected when the test finishes.
+// Unset options default to lynx.AuthNone and the MQTT options from b.MqttOptions for clientID.
+func (b *Broker) NewTestClient(t testing.TB, clientID string, opts *lynx.Options) *lynx.Client {
+	t.Helper()
+	options := lynx.Options{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Authenticator == nil {
+		options.Authenticator = lynx.AuthNone{}
+	}
+	if options.MqttOptions == nil {
+		options.MqttOptions = b.MqttOptions(clientID, nil, nil)
+	}
+	c := lynx.NewClient(&options)
+	if err := c.MQTTConnect(); err != nil {
+		t.Fatalf("MQTTConnect() error = %v", err)
+	}
+	t.Cleanup(c.MQTTDisconnect)
+	return c
+}
+
+// URL returns the broker URL, for example tcp://127.0.0.1:41234
+func (b *Broker) URL() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+// MqttOptions returns client options for the broker created by lynx.NewMqttOptions.
+func (b *Broker) MqttOptions(clientID string, onConnect mqtt.OnConnectHandler, onLost mqtt.ConnectionLostHandler) *mqtt.ClientOptions {
+	conf := viper.New()
+	conf.Set("broker", b.URL())
+	conf.Set("client_id", clientID)
+	return lynx.NewMqttOptions(conf, onConnect, onLost)
+}
+
+// Close stops the broker and closes all connections.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	b.listener.Close()
+	b.DropConnections()
+}
+
+// DropConnections closes all client connections without sending anything,
+// simulating a lost connection. Will messages are published.
+func (b *Broker) DropConnections() {
+	b.mu.Lock()
+	conns := make([]*brokerConn, 0, len(b.conns))
+	for c := range b.conns {
+		conns = append(conns, c)
+	}
+	b.mu.Unlock()
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+// Published returns all messages published by clients so far, in the order they were received.
+func (b *Broker) Published() []Publish {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.published)
+}
+
+// WaitForPublish waits for a message published by a client on a topic matching filter and returns the first one,
+// including messages published before the call. It returns false if no message arrived within timeout.
+func (b *Broker) WaitForPublish(filter string, timeout time.Duration) (Publish, bool) {
+	timer := time.AfterFunc(timeout, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.cond.Broadcast()
+	})
+	defer timer.Stop()
+	deadline := time.Now().Add(timeout)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		for _, p := range b.published {
+			if lynx.MatchTopic(filter, p.Topic) {
+				return p, true
+			}
+		}
+		if b.closed || !time.Now().Before(deadline) {
+			return Publish{}, false
+		}
+		b.cond.Wait()
+	}
+}
+
+// Inject delivers a payload to all subscribers of topic as if it was published by another client.
+// Injected messages are not included in Published.
+func (b *Broker) Inject(topic string, qos byte, retained bool, payload []byte) {
+	b.route(Publish{Topic: topic, QoS: qos, Retained: retained, Payload: payload})
+}
+
+// InjectMessage delivers a JSON encoded Lynx message to all subscribers of topic.
+func (b *Broker) InjectMessage(topic string, msg lynx.Message) {
+	data, _ := json.Marshal(msg)
+	b.Inject(topic, 0, false, data)
+}
+
+// ClientIDs returns the client ids of the currently connected clients.
+func (b *Broker) ClientIDs() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	res := make([]string, 0, len(b.conns))
+	for c := range b.conns {
+		res = append(res, c.clientID)
+	}
+	slices.Sort(res)
+	return res
+}
+
+func (b *Broker) accept() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.serve(&brokerConn{Conn: conn, subs: make(map[string]byte)})
+	}
+}
+
+func (c *brokerConn) write(p packets.ControlPacket) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	return p.Write(c.Conn)
+}
+
+func (b *Broker) serve(c *brokerConn) {
+	defer c.Close()
+	first, err := packets.ReadPacket(c)
+	if err != nil {
+		return
+	}
+	connect, ok := first.(*packets.ConnectPacket)
+	if !ok {
+		return
+	}
+	connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+	if b.Authenticate != nil && !b.Authenticate(connect.ClientIdentifier, connect.Username, string(connect.Password)) {
+		connack.ReturnCode = packets.ErrRefusedNotAuthorised
+		_ = c.write(connack)
+		return
+	}
+	c.clientID = connect.ClientIdentifier
+	if connect.WillFlag {
+		c.will = &Publish{
+			ClientID: c.clientID,
+			Topic:    connect.WillTopic,
+			QoS:      connect.WillQos,
+			Retained: connect.WillRetain,
+			Payload:  connect.WillMessage,
+		}
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.conns[c] = true
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, c)
+		b.mu.Unlock()
+		if c.will != nil {
+			b.route(*c.will)
+		}
+	}()
+	if err := c.write(connack); err != nil {
+		return
+	}
+	for {
+		if connect.Keepalive > 0 {
+			_ = c.SetReadDeadline(time.Now().Add(time.Duration(connect.Keepalive) * time.Second * 3 / 2))
+		}
+		p, err := packets.ReadPacket(c)
+		if err != nil {
+			return
+		}
+		switch p := p.(type) {
+		case *packets.PublishPacket:
+			b.received(c, p)
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			err = c.write(comp)
+		case *packets.SubscribePacket:
+			err = b.subscribe(c, p)
+		case *packets.UnsubscribePacket:
+			b.mu.Lock()
+			for _, topic := range p.Topics {
+				delete(c.subs, topic)
+			}
+			b.mu.Unlock()
+			ack := packets.NewControlPacket(packets.Unsuback).(*packets.UnsubackPacket)
+			ack.MessageID = p.MessageID
+			err = c.write(ack)
+		case *packets.PingreqPacket:
+			err = c.write(packets.NewControlPacket(packets.Pingresp))
+		case *packets.DisconnectPacket:
+			c.will = nil
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// received records a published message before acknowledging it, so that it is included in
+// Published as soon as the publishing client considers it delivered.
+func (b *Broker) received(c *brokerConn, p *packets.PublishPacket) {
+	msg := Publish{
+		ClientID: c.clientID,
+		Topic:    p.TopicName,
+		QoS:      p.Qos,
+		Retained: p.Retain,
+		Payload:  p.Payload,
+	}
+	b.mu.Lock()
+	duplicate := p.Qos > 0 && p.Dup && b.redelivery(msg, p.MessageID)
+	if !duplicate {
+		b.published = append(b.published, msg)
+		if p.Qos > 0 {
+			if b.acked[c.clientID] == nil {
+				b.acked[c.clientID] = make(map[uint16]Publish)
+			}
+			b.acked[c.clientID][p.MessageID] = msg
+		}
+		b.cond.Broadcast()
+	}
+	b.mu.Unlock()
+	switch p.Qos {
+	case 1:
+		ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+		ack.MessageID = p.MessageID
+		_ = c.write(ack)
+	case 2:
+		rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+		rec.MessageID = p.MessageID
+		_ = c.write(rec)
+	}
+	if !duplicate {
+		b.route(msg)
+	}
+}
+
+// redelivery reports whether msg is a redelivery of the last message received with the same id from the
+// client, whose acknowledgement was lost. Repeated payloads sent with new message ids are not redeliveries.
+// b.mu must be held.
+func (b *Broker) redelivery(msg Publish, id uint16) bool {
+	last, ok := b.acked[msg.ClientID][id]
+	return ok && last.Topic == msg.Topic && string(last.Payload) == string(msg.Payload)
+}
+
+func (b *Broker) subscribe(c *brokerConn, p *packets.SubscribePacket) error {
+	ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+	ack.MessageID = p.MessageID
+	var retained []Publish
+	b.mu.Lock()
+	for i, filter := range p.Topics {
+		qos := min(p.Qoss[i], 1)
+		if !validFilter(filter) {
+			ack.ReturnCodes = append(ack.ReturnCodes, 0x80)
+			continue
+		}
+		c.subs[filter] = qos
+		ack.ReturnCodes = append(ack.ReturnCodes, qos)
+		for topic, msg := range b.retained {
+			if lynx.MatchTopic(filter, topic) {
+				msg.QoS = min(msg.QoS, qos)
+				retained = append(retained, msg)
+			}
+		}
+	}
+	b.mu.Unlock()
+	if err := c.write(ack); err != nil {
+		return err
+	}
+	for _, msg := range retained {
+		if err := c.deliver(msg, msg.QoS); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// route delivers a message to all matching subscriptions.
+func (b *Broker) route(msg Publish) {
+	type delivery struct {
+		conn *brokerConn
+		qos  byte
+	}
+	var targets []delivery
+	b.mu.Lock()
+	if msg.Retained {
+		if len(msg.Payload) == 0 {
+			delete(b.retained, msg.Topic)
+		} else {
+			b.retained[msg.Topic] = msg
+		}
+	}
+	for c := range b.conns {
+		granted, matched := byte(0), false
+		for filter, qos := range c.subs {
+			if lynx.MatchTopic(filter, msg.Topic) {
+				granted, matched = max(granted, qos), true
+			}
+		}
+		if matched {
+			targets = append(targets, delivery{conn: c, qos: min(granted, msg.QoS)})
+		}
+	}
+	b.mu.Unlock()
+	msg.Retained = false
+	for _, t := range targets {
+		_ = t.conn.deliver(msg, t.qos)
+	}
+}
+
+func (c *brokerConn) deliver(msg Publish, qos byte) error {
+	p := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	p.TopicName = msg.Topic
+	p.Payload = msg.Payload
+	p.Qos = qos
+	p.Retain = msg.Retained
+	if qos > 0 {
+		c.wmu.Lock()
+		c.nextID++
+		if c.nextID == 0 {
+			c.nextID = 1
+		}
+		p.MessageID = c.nextID
+		c.wmu.Unlock()
+	}
+	return c.write(p)
+}
+
+func validFilter(filter string) bool {
+	if filter == "" {
+		return false
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package lynxtest
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+func TestBroker(t *testing.T) {
+	b := NewTestBroker(t)
+
+	c := b.NewTestClient(t, "test", &lynx.Options{Authenticator: lynx.AuthApiKey{Key: "secret"}})
+
+	received := make(chan lynx.Message, 1)
+	token := c.Mqtt.Subscribe("obj/+/value", 1, func(_ mqtt.Client, m mqtt.Message) {
+		msg, _ := Publish{Payload: m.Payload()}.Message()
+		received <- msg
+	})
+	if !token.WaitTimeout(time.Second) || token.Error() != nil {
+		t.Fatalf("Subscribe() error = %v", token.Error())
+	}
+	b.InjectMessage("obj/dev/value", lynx.Message{Value: 42})
+	select {
+	case msg := <-received:
+		if msg.Value != 42 {
+			t.Errorf("received %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("injected message not received")
+	}
+
+	if err := c.Publish("set/dev/value", lynx.Message{Value: 1}, 1); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	p, ok := b.WaitForPublish("set/#", time.Second)
+	if !ok {
+		t.Fatalf("WaitForPublish() timed out")
+	}
+	if msg, err := p.Message(); err != nil || msg.Value != 1 || p.ClientID != "test" {
+		t.Errorf("WaitForPublish() = %+v, %v", p, err)
+	}
+}
+
+func TestBroker_Redelivery(t *testing.T) {
+	b := NewTestBroker(t)
+	conn, err := net.Dial("tcp", strings.TrimPrefix(b.URL(), "tcp://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	connect := packets.NewControlPacket(packets.Connect).(*packets.ConnectPacket)
+	connect.ProtocolName, connect.ProtocolVersion, connect.ClientIdentifier = "MQTT", 4, "raw"
+	if err := connect.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := packets.ReadPacket(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	publish := func(id uint16, dup bool) {
+		t.Helper()
+		p := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+		p.TopicName, p.Payload, p.Qos, p.MessageID, p.Dup = "obj/a", []byte("same"), 1, id, dup
+		if err := p.Write(conn); err != nil {
+			t.Fatal(err)
+		}
+		ack, err := packets.ReadPacket(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ack, ok := ack.(*packets.PubackPacket); !ok || ack.MessageID != id {
+			t.Fatalf("ack = %v, want PUBACK %d", ack, id)
+		}
+	}
+	// The message is recorded before the acknowledgement is sent
+	publish(1, false)
+	if n := len(b.Published()); n != 1 {
+		t.Fatalf("Published() after PUBACK = %d messages, want 1", n)
+	}
+	// The same payload with a new message id is a new message, with the same id a redelivery
+	publish(2, true)
+	publish(2, true)
+	publish(1, true)
+	if n := len(b.Published()); n != 2 {
+		t.Errorf("Published() = %d messages, want 2", n)
+	}
+}
@@ -0,0 +1,226 @@
+package lynxtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+// AddEdgeApp adds an edge app, assigning an id if it has none, and returns it.
+func (s *Server) AddEdgeApp(app *lynx.EdgeApp) *lynx.EdgeApp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	app = clone(app)
+	if app.ID == 0 {
+		app.ID = s.nextID()
+	}
+	s.edgeApps[app.ID] = app
+	return clone(app)
+}
+
+// AddEdgeAppVersion adds a version with the given app.lua and app.json content to the app and returns its hash.
+// If name is not empty the version is also published with that name.
+func (s *Server) AddEdgeAppVersion(appID int64, name string, lua, appJSON []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addEdgeAppVersion(appID, name, lua, appJSON)
+}
+
+func (s *Server) addEdgeAppVersion(appID int64, name string, lua, appJSON []byte) string {
+	h := sha256.New()
+	h.Write(lua)
+	h.Write(appJSON)
+	hash := hex.EncodeToString(h.Sum(nil))
+	s.edgeVersions[appID] = append(s.edgeVersions[appID], &edgeAppVersion{
+		name:    name,
+		hash:    hash,
+		lua:     lua,
+		appJSON: appJSON,
+	})
+	return hash
+}
+
+// EdgeAppInstances returns all edge app instances in the installation.
+func (s *Server) EdgeAppInstances(installationID int64) []*lynx.EdgeAppConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.edgeInstances, func(c *lynx.EdgeAppConfig) bool {
+		return c.InstallationID == installationID
+	})
+}
+
+func (s *Server) edgeAppVersion(appID int64, version string) *edgeAppVersion {
+	for _, v := range s.edgeVersions[appID] {
+		if v.hash == version || (v.name != "" && v.name == version) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *Server) edgeInstance(w http.ResponseWriter, r *http.Request) (*lynx.EdgeAppConfig, bool) {
+	id, ok := ids(w, r, "installation", "id")
+	if !ok {
+		return nil, false
+	}
+	c, exists := s.edgeInstances[id[1]]
+	if !exists || c.InstallationID != id[0] {
+		notFound(w)
+		return nil, false
+	}
+	return c, true
+}
+
+func (s *Server) edgeAppRoutes(mux *http.ServeMux) {
+	const prefix = "/api/v2/edge/app"
+	mux.HandleFunc("GET "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, sortedValues(s.edgeApps, nil))
+	})
+	mux.HandleFunc("POST "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		app := &lynx.EdgeApp{}
+		if !readJSON(w, r, app) {
+			return
+		}
+		app.ID = s.nextID()
+		s.edgeApps[app.ID] = app
+		writeJSON(w, app)
+	})
+	mux.HandleFunc("GET "+prefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := pathID(r, "id")
+		if app, ok := s.edgeApps[id]; ok {
+			writeJSON(w, app)
+			return
+		}
+		notFound(w)
+	})
+	// The organization and configured endpoints overlap with the per app endpoints,
+	// so they share handlers dispatching on the first path element.
+	mux.HandleFunc("GET "+prefix+"/{a}/{b}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("a") {
+		case "organization":
+			organizationID, _ := strconv.ParseInt(r.PathValue("b"), 10, 64)
+			available := queryBool(r, "available")
+			writeJSON(w, sortedValues(s.edgeApps, func(app *lynx.EdgeApp) bool {
+				return (available && app.Public) || (app.Publisher != nil && app.Publisher.ID == organizationID)
+			}))
+			return
+		case "configured":
+			installationID, _ := strconv.ParseInt(r.PathValue("b"), 10, 64)
+			writeJSON(w, sortedValues(s.edgeInstances, func(c *lynx.EdgeAppConfig) bool {
+				return c.InstallationID == installationID
+			}))
+			return
+		}
+		appID, err := strconv.ParseInt(r.PathValue("a"), 10, 64)
+		if err != nil {
+			notFound(w)
+			return
+		}
+		switch r.PathValue("b") {
+		case "version":
+			untagged := queryBool(r, "untagged")
+			res := make([]*lynx.EdgeAppVersion, 0)
+			for _, v := range s.edgeVersions[appID] {
+				if v.name != "" || untagged {
+					res = append(res, &lynx.EdgeAppVersion{Name: v.name, Hash: v.hash})
+				}
+			}
+			writeJSON(w, res)
+		case "download":
+			if v := s.edgeAppVersion(appID, r.URL.Query().Get("version")); v != nil {
+				_, _ = w.Write(v.lua)
+				return
+			}
+			notFound(w)
+		case "configure":
+			if v := s.edgeAppVersion(appID, r.URL.Query().Get("version")); v != nil {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(v.appJSON)
+				return
+			}
+			notFound(w)
+		default:
+			notFound(w)
+		}
+	})
+	mux.HandleFunc("POST "+prefix+"/{a}/{b}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("a") == "configured" {
+			installationID, _ := strconv.ParseInt(r.PathValue("b"), 10, 64)
+			c := &lynx.EdgeAppConfig{}
+			if !readJSON(w, r, c) {
+				return
+			}
+			c.ID = s.nextID()
+			c.InstallationID = installationID
+			s.edgeInstances[c.ID] = c
+			writeJSON(w, c)
+			return
+		}
+		appID, err := strconv.ParseInt(r.PathValue("a"), 10, 64)
+		if _, exists := s.edgeApps[appID]; err != nil || !exists {
+			notFound(w)
+			return
+		}
+		switch r.PathValue("b") {
+		case "version":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			lua, appJSON := formFile(r, "app_lua"), formFile(r, "app_json")
+			writeJSON(w, map[string]string{"hash": s.addEdgeAppVersion(appID, "", lua, appJSON)})
+		case "publish":
+			version := &lynx.EdgeAppVersion{}
+			if !readJSON(w, r, version) {
+				return
+			}
+			v := s.edgeAppVersion(appID, version.Hash)
+			if v == nil {
+				notFound(w)
+				return
+			}
+			v.name = version.Name
+			writeJSON(w, version)
+		default:
+			notFound(w)
+		}
+	})
+	mux.HandleFunc("GET "+prefix+"/configured/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := s.edgeInstance(w, r); ok {
+			writeJSON(w, c)
+		}
+	})
+	mux.HandleFunc("PUT "+prefix+"/configured/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		old, ok := s.edgeInstance(w, r)
+		if !ok {
+			return
+		}
+		c := &lynx.EdgeAppConfig{}
+		if !readJSON(w, r, c) {
+			return
+		}
+		c.ID = old.ID
+		c.InstallationID = old.InstallationID
+		s.edgeInstances[c.ID] = c
+		writeJSON(w, c)
+	})
+	mux.HandleFunc("DELETE "+prefix+"/configured/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := s.edgeInstance(w, r); ok {
+			delete(s.edgeInstances, c.ID)
+		}
+	})
+}
+
+func formFile(r *http.Request, name string) []byte {
+	f, _, err := r.FormFile(name)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	content, _ := io.ReadAll(f)
+	return content
+}
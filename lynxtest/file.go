@@ -0,0 +1,157 @@
+package lynxtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+// AddFile adds a file with the given content, assigning an id and hash if it has none, and returns it.
+// The file belongs to an installation or an organization depending on which id is set.
+func (s *Server) AddFile(f *lynx.File, content []byte) *lynx.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f = clone(f)
+	if f.ID == 0 {
+		f.ID = s.nextID()
+	}
+	if f.Hash == "" {
+		f.Hash = fileHash(content)
+	}
+	s.files[f.ID] = &storedFile{file: f, content: content}
+	return clone(f)
+}
+
+// FileContent returns the content of the file with the given hash.
+func (s *Server) FileContent(hash string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range s.files {
+		if f.file.Hash == hash {
+			return f.content, true
+		}
+	}
+	return nil, false
+}
+
+func fileHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// partFileName returns the file name of a multipart part, falling back to
+// a lenient parsing of the Content-Disposition header for doubly quoted names.
+func partFileName(part *multipart.Part) string {
+	if name := part.FileName(); name != "" {
+		return name
+	}
+	_, name, found := strings.Cut(part.Header.Get("Content-Disposition"), "filename=")
+	if !found {
+		return "file"
+	}
+	return filepath.Base(strings.Trim(name, `"\`))
+}
+
+// readUpload reads the first part of a multipart upload.
+func readUpload(w http.ResponseWriter, r *http.Request) (name, mime string, content []byte, ok bool) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", "", nil, false
+	}
+	part, err := reader.NextPart()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", "", nil, false
+	}
+	defer part.Close()
+	content, err = io.ReadAll(part)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", "", nil, false
+	}
+	return partFileName(part), part.Header.Get("Content-Type"), content, true
+}
+
+func (s *Server) fileRoutes(mux *http.ServeMux) {
+	scopes := map[string]func(f *lynx.File) *int64{
+		"installation": func(f *lynx.File) *int64 { return &f.InstallationID },
+		"organization": func(f *lynx.File) *int64 { return &f.OrganizationID },
+	}
+	for scope, owner := range scopes {
+		prefix := "/api/v2/file/" + scope + "/{owner}"
+		find := func(w http.ResponseWriter, r *http.Request) (*storedFile, bool) {
+			id, ok := ids(w, r, "owner", "id")
+			if !ok {
+				return nil, false
+			}
+			f, exists := s.files[id[1]]
+			if !exists || *owner(f.file) != id[0] {
+				notFound(w)
+				return nil, false
+			}
+			return f, true
+		}
+		mux.HandleFunc("GET "+prefix, func(w http.ResponseWriter, r *http.Request) {
+			ownerID, _ := pathID(r, "owner")
+			res := make([]*lynx.File, 0)
+			for _, f := range s.files {
+				if *owner(f.file) == ownerID {
+					res = append(res, f.file)
+				}
+			}
+			orderBy(res, false, func(f *lynx.File) float64 { return float64(f.ID) })
+			writeJSON(w, res)
+		})
+		mux.HandleFunc("GET "+prefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if f, ok := find(w, r); ok {
+				writeJSON(w, f.file)
+			}
+		})
+		mux.HandleFunc("POST "+prefix, func(w http.ResponseWriter, r *http.Request) {
+			ownerID, _ := pathID(r, "owner")
+			name, mime, content, ok := readUpload(w, r)
+			if !ok {
+				return
+			}
+			f := &lynx.File{ID: s.nextID(), Name: name, MIME: mime, Hash: fileHash(content)}
+			*owner(f) = ownerID
+			s.files[f.ID] = &storedFile{file: f, content: content}
+			writeJSON(w, []*lynx.File{f})
+		})
+		mux.HandleFunc("POST "+prefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+			f, ok := find(w, r)
+			if !ok {
+				return
+			}
+			name, mime, content, ok := readUpload(w, r)
+			if !ok {
+				return
+			}
+			f.file.Name, f.file.MIME, f.file.Hash = name, mime, fileHash(content)
+			f.content = content
+			writeJSON(w, f.file)
+		})
+		mux.HandleFunc("DELETE "+prefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if f, ok := find(w, r); ok {
+				delete(s.files, f.file.ID)
+			}
+		})
+	}
+	mux.HandleFunc("GET /api/v2/file/download/{hash}", func(w http.ResponseWriter, r *http.Request) {
+		for _, f := range s.files {
+			if f.file.Hash == r.PathValue("hash") {
+				w.Header().Set("Content-Type", f.file.MIME)
+				_, _ = w.Write(f.content)
+				return
+			}
+		}
+		notFound(w)
+	})
+}
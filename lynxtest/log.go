@@ -0,0 +1,150 @@
+package lynxtest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+// AddLog adds log entries to the installation. The latest entry per topic is returned by the status endpoint.
+func (s *Server) AddLog(installationID int64, entries ...lynx.LogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		e.InstallationID = installationID
+		s.logs[installationID] = append(s.logs[installationID], e)
+	}
+}
+
+// Log returns all log entries of the installation in the order they were added.
+func (s *Server) Log(installationID int64) []lynx.LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.logs[installationID])
+}
+
+// AddTrace adds trace entries.
+func (s *Server) AddTrace(entries ...lynx.TraceEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.traces = append(s.traces, entries...)
+}
+
+// timeWindow reports whether timestamp is within the from and to query parameters, given in unix seconds.
+func timeWindow(r *http.Request, timestamp float64) bool {
+	query := r.URL.Query()
+	if from, err := strconv.ParseInt(query.Get("from"), 10, 64); err == nil && timestamp < float64(from) {
+		return false
+	}
+	if to, err := strconv.ParseInt(query.Get("to"), 10, 64); err == nil && timestamp >= float64(to+1) {
+		return false
+	}
+	return true
+}
+
+// topicFilter returns the topics to filter on, from the query or a JSON body for POST requests.
+func topicFilter(w http.ResponseWriter, r *http.Request) (map[string]bool, bool) {
+	topics := r.URL.Query()["topics"]
+	if r.Method == http.MethodPost {
+		if !readJSON(w, r, &topics) {
+			return nil, false
+		}
+	}
+	if len(topics) == 0 {
+		return nil, true
+	}
+	res := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		res[t] = true
+	}
+	return res, true
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	installationID, _ := pathID(r, "installation")
+	topics, ok := topicFilter(w, r)
+	if !ok {
+		return
+	}
+	latest := make(map[string]lynx.LogEntry)
+	for _, e := range s.logs[installationID] {
+		if topics != nil && !topics[e.Topic] {
+			continue
+		}
+		if old, exists := latest[e.Topic]; !exists || e.Timestamp >= old.Timestamp {
+			latest[e.Topic] = e
+		}
+	}
+	res := make(lynx.Status, 0, len(latest))
+	for _, e := range latest {
+		res = append(res, &e)
+	}
+	slices.SortFunc(res, func(a, b *lynx.LogEntry) int {
+		return cmp.Compare(a.Topic, b.Topic)
+	})
+	writeJSON(w, res)
+}
+
+func (s *Server) log(w http.ResponseWriter, r *http.Request) {
+	installationID, _ := pathID(r, "installation")
+	topics, ok := topicFilter(w, r)
+	if !ok {
+		return
+	}
+	entries := make([]lynx.LogEntry, 0)
+	for _, e := range s.logs[installationID] {
+		if (topics == nil || topics[e.Topic]) && timeWindow(r, e.Timestamp) {
+			entries = append(entries, e)
+		}
+	}
+	orderBy(entries, r.URL.Query().Get("order") != string(lynx.LogOrderAsc), func(e lynx.LogEntry) float64 {
+		return e.Timestamp
+	})
+	res := lynx.V3Log{Total: int64(len(entries)), Data: page(entries, r)}
+	res.Count = len(res.Data)
+	if res.Count > 0 {
+		res.LastTime = res.Data[res.Count-1].Timestamp
+	}
+	writeJSON(w, res)
+}
+
+func (s *Server) trace(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	entries := make([]lynx.TraceEntry, 0)
+	for _, e := range s.traces {
+		if !timeWindow(r, e.Timestamp) {
+			continue
+		}
+		if objectType := query.Get("object_type"); objectType != "" {
+			if string(e.ObjectType) != objectType || query.Get("object_id") != strconv.FormatInt(e.ObjectID, 10) {
+				continue
+			}
+		} else if id := query.Get("id"); id != "" && e.ID != id {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	orderBy(entries, query.Get("order") != string(lynx.LogOrderAsc), func(e lynx.TraceEntry) float64 {
+		return e.Timestamp
+	})
+	res := lynx.TracePage{Total: int64(len(entries)), Data: page(entries, r)}
+	res.Count = len(res.Data)
+	if res.Count > 0 {
+		res.LastTime = res.Data[res.Count-1].Timestamp
+	}
+	writeJSON(w, res)
+}
+
+func (s *Server) traceRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/trace", s.trace)
+}
+
+func (s *Server) logRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/status/{installation}", s.status)
+	mux.HandleFunc("POST /api/v2/status/{installation}", s.status)
+	mux.HandleFunc("GET /api/v3beta/log/{installation}", s.log)
+	mux.HandleFunc("POST /api/v3beta/log/{installation}", s.log)
+}
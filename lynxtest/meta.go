@@ -0,0 +1,85 @@
+package lynxtest
+
+import (
+	"net/http"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+// metaLookup finds the meta maps of the object addressed by the request, writing an error response if it does not exist.
+type metaLookup func(w http.ResponseWriter, r *http.Request) (meta, protected *lynx.Meta, ok bool)
+
+func metaRoutes(mux *http.ServeMux, prefix string, lookup metaLookup) {
+	path := prefix + "/meta/{key}"
+	mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+		meta, protected, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		key := r.PathValue("key")
+		if v, exists := (*protected)[key]; exists {
+			writeJSON(w, lynx.MetaObject{Value: v, Protected: true})
+		} else if v, exists := (*meta)[key]; exists {
+			writeJSON(w, lynx.MetaObject{Value: v})
+		} else {
+			notFound(w)
+		}
+	})
+	set := func(w http.ResponseWriter, r *http.Request, create bool) {
+		meta, protected, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		mo := lynx.MetaObject{}
+		if !readJSON(w, r, &mo) {
+			return
+		}
+		key := r.PathValue("key")
+		_, inMeta := (*meta)[key]
+		_, inProtected := (*protected)[key]
+		exists := inMeta || inProtected
+		if create && exists {
+			writeError(w, http.StatusConflict, "meta key already exists")
+			return
+		}
+		if !create && !exists && !queryBool(r, "create_missing") {
+			notFound(w)
+			return
+		}
+		if *meta == nil {
+			*meta = make(lynx.Meta)
+		}
+		if *protected == nil {
+			*protected = make(lynx.Meta)
+		}
+		delete(*meta, key)
+		delete(*protected, key)
+		if mo.Protected {
+			(*protected)[key] = mo.Value
+		} else {
+			(*meta)[key] = mo.Value
+		}
+		writeJSON(w, mo)
+	}
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		set(w, r, true)
+	})
+	mux.HandleFunc("PUT "+path, func(w http.ResponseWriter, r *http.Request) {
+		set(w, r, false)
+	})
+	mux.HandleFunc("DELETE "+path, func(w http.ResponseWriter, r *http.Request) {
+		meta, protected, ok := lookup(w, r)
+		if !ok {
+			return
+		}
+		key := r.PathValue("key")
+		_, inMeta := (*meta)[key]
+		_, inProtected := (*protected)[key]
+		if !inMeta && !inProtected {
+			notFound(w)
+			return
+		}
+		delete(*meta, key)
+		delete(*protected, key)
+	})
+}
@@ -0,0 +1,212 @@
+package lynxtest
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+// AddNotificationMessage adds a notification message to the installation, assigning an id if it has none, and returns it.
+func (s *Server) AddNotificationMessage(installationID int64, msg *lynx.NotificationMessage) *lynx.NotificationMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg = clone(msg)
+	if msg.ID == 0 {
+		msg.ID = s.nextID()
+	}
+	s.messages[msg.ID] = &notificationMessage{installationID: installationID, message: msg}
+	return clone(msg)
+}
+
+// AddNotificationOutput adds a notification output, assigning an id if it has none, and returns it.
+func (s *Server) AddNotificationOutput(o *lynx.NotificationOutput) *lynx.NotificationOutput {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o = clone(o)
+	if o.ID == 0 {
+		o.ID = s.nextID()
+	}
+	s.outputs[o.ID] = o
+	return clone(o)
+}
+
+// AddNotificationOutputExecutor adds an output executor, assigning an id if it has none, and returns it.
+// Executors are available to all installations.
+func (s *Server) AddNotificationOutputExecutor(ex *lynx.NotificationOutputExecutor) *lynx.NotificationOutputExecutor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ex = clone(ex)
+	if ex.ID == 0 {
+		ex.ID = s.nextID()
+	}
+	s.executors[ex.ID] = ex
+	return clone(ex)
+}
+
+// NotificationOutputs returns all notification outputs in the installation.
+func (s *Server) NotificationOutputs(installationID int64) []*lynx.NotificationOutput {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.outputs, func(o *lynx.NotificationOutput) bool {
+		return o.InstallationID == installationID
+	})
+}
+
+// Notifications returns all notifications sent so far.
+func (s *Server) Notifications() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]Notification, len(s.notifications))
+	copy(res, s.notifications)
+	return res
+}
+
+func (s *Server) notificationMessage(w http.ResponseWriter, r *http.Request) (*notificationMessage, bool) {
+	id, ok := ids(w, r, "installation", "id")
+	if !ok {
+		return nil, false
+	}
+	msg, exists := s.messages[id[1]]
+	if !exists || msg.installationID != id[0] {
+		notFound(w)
+		return nil, false
+	}
+	return msg, true
+}
+
+func (s *Server) notificationOutput(w http.ResponseWriter, r *http.Request) (*lynx.NotificationOutput, bool) {
+	id, ok := ids(w, r, "installation", "id")
+	if !ok {
+		return nil, false
+	}
+	o, exists := s.outputs[id[1]]
+	if !exists || o.InstallationID != id[0] {
+		notFound(w)
+		return nil, false
+	}
+	return o, true
+}
+
+func (s *Server) notificationRoutes(mux *http.ServeMux) {
+	const prefix = "/api/v2/notification/{installation}"
+	mux.HandleFunc("GET "+prefix+"/message", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		res := make([]*lynx.NotificationMessage, 0)
+		for _, id := range slices.Sorted(maps.Keys(s.messages)) {
+			if msg := s.messages[id]; msg.installationID == installationID {
+				res = append(res, msg.message)
+			}
+		}
+		writeJSON(w, res)
+	})
+	mux.HandleFunc("GET "+prefix+"/message/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if msg, ok := s.notificationMessage(w, r); ok {
+			writeJSON(w, msg.message)
+		}
+	})
+	mux.HandleFunc("POST "+prefix+"/message", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		msg := &lynx.NotificationMessage{}
+		if !readJSON(w, r, msg) {
+			return
+		}
+		msg.ID = s.nextID()
+		s.messages[msg.ID] = &notificationMessage{installationID: installationID, message: msg}
+		writeJSON(w, msg)
+	})
+	mux.HandleFunc("PUT "+prefix+"/message/{id}", func(w http.ResponseWriter, r *http.Request) {
+		old, ok := s.notificationMessage(w, r)
+		if !ok {
+			return
+		}
+		msg := &lynx.NotificationMessage{}
+		if !readJSON(w, r, msg) {
+			return
+		}
+		msg.ID = old.message.ID
+		old.message = msg
+		writeJSON(w, msg)
+	})
+	mux.HandleFunc("DELETE "+prefix+"/message/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if msg, ok := s.notificationMessage(w, r); ok {
+			delete(s.messages, msg.message.ID)
+		}
+	})
+
+	mux.HandleFunc("GET "+prefix+"/output", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		writeJSON(w, sortedValues(s.outputs, func(o *lynx.NotificationOutput) bool {
+			return o.InstallationID == installationID
+		}))
+	})
+	mux.HandleFunc("GET "+prefix+"/output/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if o, ok := s.notificationOutput(w, r); ok {
+			writeJSON(w, o)
+		}
+	})
+	mux.HandleFunc("POST "+prefix+"/output", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		o := &lynx.NotificationOutput{}
+		if !readJSON(w, r, o) {
+			return
+		}
+		o.ID = s.nextID()
+		o.InstallationID = installationID
+		s.outputs[o.ID] = o
+		writeJSON(w, o)
+	})
+	mux.HandleFunc("PUT "+prefix+"/output/{id}", func(w http.ResponseWriter, r *http.Request) {
+		old, ok := s.notificationOutput(w, r)
+		if !ok {
+			return
+		}
+		o := &lynx.NotificationOutput{}
+		if !readJSON(w, r, o) {
+			return
+		}
+		o.ID = old.ID
+		o.InstallationID = old.InstallationID
+		s.outputs[o.ID] = o
+		writeJSON(w, o)
+	})
+	mux.HandleFunc("DELETE "+prefix+"/output/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if o, ok := s.notificationOutput(w, r); ok {
+			delete(s.outputs, o.ID)
+		}
+	})
+	mux.HandleFunc("POST "+prefix+"/output/{id}/send", func(w http.ResponseWriter, r *http.Request) {
+		o, ok := s.notificationOutput(w, r)
+		if !ok {
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil || !json.Valid(data) {
+			writeError(w, http.StatusBadRequest, "invalid payload")
+			return
+		}
+		s.notifications = append(s.notifications, Notification{
+			InstallationID: o.InstallationID,
+			OutputID:       o.ID,
+			Data:           data,
+		})
+	})
+
+	mux.HandleFunc("GET "+prefix+"/executor", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, sortedValues(s.executors, nil))
+	})
+	mux.HandleFunc("GET "+prefix+"/executor/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := ids(w, r, "id")
+		if !ok {
+			return
+		}
+		if ex, exists := s.executors[id[0]]; exists {
+			writeJSON(w, ex)
+			return
+		}
+		notFound(w)
+	})
+}
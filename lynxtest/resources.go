@@ -0,0 +1,532 @@
+package lynxtest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+// matchFilter reports whether the query parameters of r matches the meta, or the type for the key "type".
+func matchFilter(r *http.Request, typ string, meta lynx.Meta) bool {
+	for key, values := range r.URL.Query() {
+		if key == "type" {
+			if typ != values[0] {
+				return false
+			}
+		} else if meta[key] != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// AddInstallation adds an installation, assigning an id if it has none, and returns it.
+func (s *Server) AddInstallation(i *lynx.InstallationRow) *lynx.InstallationRow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i = clone(i)
+	if i.ID == 0 {
+		i.ID = s.nextID()
+	}
+	s.installations[i.ID] = i
+	return clone(i)
+}
+
+// Installations returns all installations.
+func (s *Server) Installations() []*lynx.InstallationRow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.installations, nil)
+}
+
+func installationInfo(i *lynx.InstallationRow) *lynx.Installation {
+	return &lynx.Installation{
+		ID:             i.ID,
+		ClientID:       i.ClientID,
+		Name:           i.Name,
+		OrganizationID: i.OrganizationID,
+		Assigned:       true,
+	}
+}
+
+func (s *Server) installationRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/installation", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		organization := query.Get("organization_id")
+		query.Del("organization_id")
+		r.URL.RawQuery = query.Encode()
+		writeJSON(w, sortedValues(s.installations, func(i *lynx.InstallationRow) bool {
+			if organization != "" && organization != strconv.FormatInt(i.OrganizationID, 10) {
+				return false
+			}
+			return matchFilter(r, "", i.Meta)
+		}))
+	})
+	mux.HandleFunc("GET /api/v2/installation/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := pathID(r, "id")
+		if i, ok := s.installations[id]; ok {
+			writeJSON(w, clone(i))
+			return
+		}
+		notFound(w)
+	})
+	mux.HandleFunc("PUT /api/v2/installation/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := pathID(r, "id")
+		if _, ok := s.installations[id]; !ok {
+			notFound(w)
+			return
+		}
+		i := &lynx.InstallationRow{}
+		if !readJSON(w, r, i) {
+			return
+		}
+		i.ID = id
+		s.installations[id] = i
+		writeJSON(w, i)
+	})
+	mux.HandleFunc("GET /api/v2/installationinfo", func(w http.ResponseWriter, r *http.Request) {
+		res := make([]*lynx.Installation, 0, len(s.installations))
+		for _, i := range sortedValues(s.installations, nil) {
+			res = append(res, installationInfo(i))
+		}
+		writeJSON(w, res)
+	})
+	mux.HandleFunc("GET /api/v2/installationinfo/{clientID}", func(w http.ResponseWriter, r *http.Request) {
+		clientID, _ := pathID(r, "clientID")
+		for _, i := range sortedValues(s.installations, nil) {
+			if i.ClientID == clientID {
+				writeJSON(w, installationInfo(i))
+				return
+			}
+		}
+		notFound(w)
+	})
+	metaRoutes(mux, "/api/v2/installation/{id}", func(w http.ResponseWriter, r *http.Request) (*lynx.Meta, *lynx.Meta, bool) {
+		id, _ := pathID(r, "id")
+		i, ok := s.installations[id]
+		if !ok {
+			notFound(w)
+			return nil, nil, false
+		}
+		return &i.Meta, &i.ProtectedMeta, true
+	})
+}
+
+// AddFunction adds a function, assigning an id if it has none, and returns it.
+func (s *Server) AddFunction(fn *lynx.Function) *lynx.Function {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn = clone(fn)
+	if fn.ID == 0 {
+		fn.ID = s.nextID()
+	}
+	s.functions[fn.ID] = fn
+	return clone(fn)
+}
+
+// Functions returns all functions in the installation.
+func (s *Server) Functions(installationID int64) lynx.FunctionList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.functions, func(fn *lynx.Function) bool {
+		return fn.InstallationID == installationID
+	})
+}
+
+func (s *Server) function(w http.ResponseWriter, r *http.Request) (*lynx.Function, bool) {
+	id, ok := ids(w, r, "installation", "id")
+	if !ok {
+		return nil, false
+	}
+	fn, exists := s.functions[id[1]]
+	if !exists || fn.InstallationID != id[0] {
+		notFound(w)
+		return nil, false
+	}
+	return fn, true
+}
+
+func (s *Server) functionRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/functionx/{installation}", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		writeJSON(w, sortedValues(s.functions, func(fn *lynx.Function) bool {
+			return fn.InstallationID == installationID && matchFilter(r, fn.Type, fn.Meta)
+		}))
+	})
+	mux.HandleFunc("GET /api/v2/functionx/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if fn, ok := s.function(w, r); ok {
+			writeJSON(w, fn)
+		}
+	})
+	mux.HandleFunc("POST /api/v2/functionx/{installation}", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		fn := &lynx.Function{}
+		if !readJSON(w, r, fn) {
+			return
+		}
+		fn.ID = s.nextID()
+		fn.InstallationID = installationID
+		s.functions[fn.ID] = fn
+		writeJSON(w, fn)
+	})
+	mux.HandleFunc("PUT /api/v2/functionx/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		old, ok := s.function(w, r)
+		if !ok {
+			return
+		}
+		fn := &lynx.Function{}
+		if !readJSON(w, r, fn) {
+			return
+		}
+		fn.ID = old.ID
+		fn.InstallationID = old.InstallationID
+		s.functions[fn.ID] = fn
+		writeJSON(w, fn)
+	})
+	mux.HandleFunc("DELETE /api/v2/functionx/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if fn, ok := s.function(w, r); ok {
+			delete(s.functions, fn.ID)
+		}
+	})
+	metaRoutes(mux, "/api/v2/functionx/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) (*lynx.Meta, *lynx.Meta, bool) {
+		fn, ok := s.function(w, r)
+		if !ok {
+			return nil, nil, false
+		}
+		return &fn.Meta, &fn.ProtectedMeta, true
+	})
+}
+
+// AddDevice adds a device, assigning an id if it has none, and returns it.
+func (s *Server) AddDevice(dev *lynx.Device) *lynx.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dev = clone(dev)
+	if dev.ID == 0 {
+		dev.ID = s.nextID()
+	}
+	s.devices[dev.ID] = dev
+	return clone(dev)
+}
+
+// Devices returns all devices in the installation.
+func (s *Server) Devices(installationID int64) lynx.DeviceList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.devices, func(dev *lynx.Device) bool {
+		return dev.InstallationID == installationID
+	})
+}
+
+func (s *Server) device(w http.ResponseWriter, r *http.Request) (*lynx.Device, bool) {
+	id, ok := ids(w, r, "installation", "id")
+	if !ok {
+		return nil, false
+	}
+	dev, exists := s.devices[id[1]]
+	if !exists || dev.InstallationID != id[0] {
+		notFound(w)
+		return nil, false
+	}
+	return dev, true
+}
+
+func (s *Server) deviceRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/devicex/{installation}", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		writeJSON(w, sortedValues(s.devices, func(dev *lynx.Device) bool {
+			return dev.InstallationID == installationID && matchFilter(r, dev.Type, dev.Meta)
+		}))
+	})
+	mux.HandleFunc("GET /api/v2/devicex/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if dev, ok := s.device(w, r); ok {
+			writeJSON(w, dev)
+		}
+	})
+	mux.HandleFunc("POST /api/v2/devicex/{installation}", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		dev := &lynx.Device{}
+		if !readJSON(w, r, dev) {
+			return
+		}
+		dev.ID = s.nextID()
+		dev.InstallationID = installationID
+		s.devices[dev.ID] = dev
+		writeJSON(w, dev)
+	})
+	mux.HandleFunc("PUT /api/v2/devicex/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		old, ok := s.device(w, r)
+		if !ok {
+			return
+		}
+		dev := &lynx.Device{}
+		if !readJSON(w, r, dev) {
+			return
+		}
+		dev.ID = old.ID
+		dev.InstallationID = old.InstallationID
+		s.devices[dev.ID] = dev
+		writeJSON(w, dev)
+	})
+	mux.HandleFunc("DELETE /api/v2/devicex/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if dev, ok := s.device(w, r); ok {
+			delete(s.devices, dev.ID)
+		}
+	})
+	metaRoutes(mux, "/api/v2/devicex/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) (*lynx.Meta, *lynx.Meta, bool) {
+		dev, ok := s.device(w, r)
+		if !ok {
+			return nil, nil, false
+		}
+		return &dev.Meta, &dev.ProtectedMeta, true
+	})
+}
+
+// AddOrganization adds an organization, assigning an id if it has none, and returns it.
+func (s *Server) AddOrganization(org *lynx.Organization) *lynx.Organization {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org = clone(org)
+	if org.ID == 0 {
+		org.ID = s.nextID()
+	}
+	s.organizations[org.ID] = org
+	return clone(org)
+}
+
+// Organizations returns all organizations.
+func (s *Server) Organizations() lynx.OrganizationList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.organizations, nil)
+}
+
+func (s *Server) organization(w http.ResponseWriter, r *http.Request) (*lynx.Organization, bool) {
+	id, ok := ids(w, r, "id")
+	if !ok {
+		return nil, false
+	}
+	org, exists := s.organizations[id[0]]
+	if !exists {
+		notFound(w)
+		return nil, false
+	}
+	return org, true
+}
+
+func (s *Server) organizationRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/organization", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Del("minimal")
+		r.URL.RawQuery = query.Encode()
+		writeJSON(w, sortedValues(s.organizations, func(org *lynx.Organization) bool {
+			return matchFilter(r, "", org.Meta)
+		}))
+	})
+	mux.HandleFunc("GET /api/v2/organization/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if org, ok := s.organization(w, r); ok {
+			writeJSON(w, org)
+		}
+	})
+	mux.HandleFunc("POST /api/v2/organization", func(w http.ResponseWriter, r *http.Request) {
+		org := &lynx.Organization{}
+		if !readJSON(w, r, org) {
+			return
+		}
+		org.ID = s.nextID()
+		s.organizations[org.ID] = org
+		writeJSON(w, org)
+	})
+	mux.HandleFunc("PUT /api/v2/organization/{id}", func(w http.ResponseWriter, r *http.Request) {
+		old, ok := s.organization(w, r)
+		if !ok {
+			return
+		}
+		org := &lynx.Organization{}
+		if !readJSON(w, r, org) {
+			return
+		}
+		org.ID = old.ID
+		s.organizations[org.ID] = org
+		writeJSON(w, org)
+	})
+	mux.HandleFunc("DELETE /api/v2/organization/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if org, ok := s.organization(w, r); ok {
+			delete(s.organizations, org.ID)
+		}
+	})
+	mux.HandleFunc("POST /api/v2/organization/{id}/force_password_reset", func(w http.ResponseWriter, r *http.Request) {
+		s.organization(w, r)
+	})
+	metaRoutes(mux, "/api/v2/organization/{id}", func(w http.ResponseWriter, r *http.Request) (*lynx.Meta, *lynx.Meta, bool) {
+		org, ok := s.organization(w, r)
+		if !ok {
+			return nil, nil, false
+		}
+		return &org.Meta, &org.ProtectedMeta, true
+	})
+}
+
+// AddUser adds a user, assigning an id if it has none, and returns it.
+func (s *Server) AddUser(u *lynx.User) *lynx.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u = clone(u)
+	if u.ID == 0 {
+		u.ID = s.nextID()
+	}
+	s.users[u.ID] = u
+	return clone(u)
+}
+
+// SetMe sets the user returned by the user/me endpoint, adding it if needed.
+func (s *Server) SetMe(u *lynx.User) *lynx.User {
+	u = s.AddUser(u)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.me = u.ID
+	return u
+}
+
+// Users returns all users.
+func (s *Server) Users() []*lynx.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.users, nil)
+}
+
+func (s *Server) user(w http.ResponseWriter, r *http.Request) (*lynx.User, bool) {
+	id, ok := ids(w, r, "id")
+	if !ok {
+		return nil, false
+	}
+	u, exists := s.users[id[0]]
+	if !exists {
+		notFound(w)
+		return nil, false
+	}
+	return u, true
+}
+
+func (s *Server) userRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, sortedValues(s.users, func(u *lynx.User) bool {
+			return matchFilter(r, "", u.Meta)
+		}))
+	})
+	mux.HandleFunc("GET /api/v2/user/me", func(w http.ResponseWriter, r *http.Request) {
+		if u, ok := s.users[s.me]; ok {
+			writeJSON(w, u)
+			return
+		}
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+	})
+	update := func(w http.ResponseWriter, r *http.Request, id int64) {
+		u := &lynx.User{}
+		if !readJSON(w, r, u) {
+			return
+		}
+		u.ID = id
+		s.users[id] = u
+		writeJSON(w, u)
+	}
+	mux.HandleFunc("PUT /api/v2/user/me", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.users[s.me]; !ok {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		update(w, r, s.me)
+	})
+	mux.HandleFunc("PUT /api/v2/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if u, ok := s.user(w, r); ok {
+			update(w, r, u.ID)
+		}
+	})
+	metaRoutes(mux, "/api/v2/user/{id}", func(w http.ResponseWriter, r *http.Request) (*lynx.Meta, *lynx.Meta, bool) {
+		u, ok := s.user(w, r)
+		if !ok {
+			return nil, nil, false
+		}
+		return &u.Meta, &u.ProtectedMeta, true
+	})
+}
+
+// AddSchedule adds a schedule, assigning an id if it has none, and returns it.
+func (s *Server) AddSchedule(sc *lynx.Schedule) *lynx.Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc = clone(sc)
+	if sc.ID == 0 {
+		sc.ID = s.nextID()
+	}
+	s.schedules[sc.ID] = sc
+	return clone(sc)
+}
+
+// Schedules returns all schedules in the installation.
+func (s *Server) Schedules(installationID int64) []*lynx.Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.schedules, func(sc *lynx.Schedule) bool {
+		return sc.InstallationID == installationID
+	})
+}
+
+func (s *Server) schedule(w http.ResponseWriter, r *http.Request) (*lynx.Schedule, bool) {
+	id, ok := ids(w, r, "installation", "id")
+	if !ok {
+		return nil, false
+	}
+	sc, exists := s.schedules[id[1]]
+	if !exists || sc.InstallationID != id[0] {
+		notFound(w)
+		return nil, false
+	}
+	return sc, true
+}
+
+func (s *Server) scheduleRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/schedule/{installation}", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		executor := r.URL.Query().Get("executor")
+		writeJSON(w, sortedValues(s.schedules, func(sc *lynx.Schedule) bool {
+			return sc.InstallationID == installationID && (executor == "" || sc.Executor == executor)
+		}))
+	})
+	mux.HandleFunc("GET /api/v2/schedule/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if sc, ok := s.schedule(w, r); ok {
+			writeJSON(w, sc)
+		}
+	})
+	mux.HandleFunc("POST /api/v2/schedule/{installation}", func(w http.ResponseWriter, r *http.Request) {
+		installationID, _ := pathID(r, "installation")
+		sc := &lynx.Schedule{}
+		if !readJSON(w, r, sc) {
+			return
+		}
+		sc.ID = s.nextID()
+		sc.InstallationID = installationID
+		s.schedules[sc.ID] = sc
+		writeJSON(w, sc)
+	})
+	mux.HandleFunc("PUT /api/v2/schedule/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		old, ok := s.schedule(w, r)
+		if !ok {
+			return
+		}
+		sc := &lynx.Schedule{}
+		if !readJSON(w, r, sc) {
+			return
+		}
+		sc.ID = old.ID
+		sc.InstallationID = old.InstallationID
+		s.schedules[sc.ID] = sc
+		writeJSON(w, sc)
+	})
+	mux.HandleFunc("DELETE /api/v2/schedule/{installation}/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if sc, ok := s.schedule(w, r); ok {
+			delete(s.schedules, sc.ID)
+		}
+	})
+}
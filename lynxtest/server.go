@@ -0,0 +1,229 @@
+// Package lynxtest provides an in-memory implementation of the Lynx API for use in tests.
+//
+// A Server is seeded with objects using the Add methods, a lynx.Client pointed at it with
+// Server.Client and the resulting state inspected with the accessor methods:
+//
+//	srv := lynxtest.NewServer()
+//	defer srv.Close()
+//	srv.AddFunction(&lynx.Function{InstallationID: 1, Type: "temperature"})
+//	functions, err := srv.Client().GetFunctions(1, lynx.Filter{})
+package lynxtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+// Notification is a notification sent through the server
+type Notification struct {
+	InstallationID int64
+	OutputID       int64
+	Data           json.RawMessage
+}
+
+type storedFile struct {
+	file    *lynx.File
+	content []byte
+}
+
+type edgeAppVersion struct {
+	name    string
+	hash    string
+	lua     []byte
+	appJSON []byte
+}
+
+// Server is an in-memory Lynx API server. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	lastID        int64
+	requests      []Request
+	installations map[int64]*lynx.InstallationRow
+	functions     map[int64]*lynx.Function
+	devices       map[int64]*lynx.Device
+	organizations map[int64]*lynx.Organization
+	users         map[int64]*lynx.User
+	me            int64
+	schedules     map[int64]*lynx.Schedule
+	messages      map[int64]*notificationMessage
+	outputs       map[int64]*lynx.NotificationOutput
+	executors     map[int64]*lynx.NotificationOutputExecutor
+	notifications []Notification
+	files         map[int64]*storedFile
+	edgeApps      map[int64]*lynx.EdgeApp
+	edgeVersions  map[int64][]*edgeAppVersion
+	edgeInstances map[int64]*lynx.EdgeAppConfig
+	traces        []lynx.TraceEntry
+	logs          map[int64][]lynx.LogEntry
+}
+
+type notificationMessage struct {
+	installationID int64
+	message        *lynx.NotificationMessage
+}
+
+// NewServer starts and returns a new empty Server. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		installations: make(map[int64]*lynx.InstallationRow),
+		functions:     make(map[int64]*lynx.Function),
+		devices:       make(map[int64]*lynx.Device),
+		organizations: make(map[int64]*lynx.Organization),
+		users:         make(map[int64]*lynx.User),
+		schedules:     make(map[int64]*lynx.Schedule),
+		messages:      make(map[int64]*notificationMessage),
+		outputs:       make(map[int64]*lynx.NotificationOutput),
+		executors:     make(map[int64]*lynx.NotificationOutputExecutor),
+		files:         make(map[int64]*storedFile),
+		edgeApps:      make(map[int64]*lynx.EdgeApp),
+		edgeVersions:  make(map[int64][]*edgeAppVersion),
+		edgeInstances: make(map[int64]*lynx.EdgeAppConfig),
+		logs:          make(map[int64][]lynx.LogEntry),
+	}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// Client returns a new lynx.Client using the server, with no authentication.
+func (s *Server) Client() *lynx.Client {
+	return lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.AuthNone{},
+		APIBase:       s.URL,
+	})
+}
+
+// Requests returns all requests received by the server so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+// clone returns a deep copy of v by a round trip through JSON, the same way the real API would.
+func clone[T any](v *T) *T {
+	res := new(T)
+	bin, _ := json.Marshal(v)
+	_ = json.Unmarshal(bin, res)
+	return res
+}
+
+func sortedValues[T any](m map[int64]*T, keep func(*T) bool) []*T {
+	res := make([]*T, 0, len(m))
+	for _, id := range slices.Sorted(maps.Keys(m)) {
+		if keep == nil || keep(m[id]) {
+			res = append(res, clone(m[id]))
+		}
+	}
+	return res
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(lynx.Error{Message: message})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "not found")
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// pathID parses the named path value as an id.
+func pathID(r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	return id, err == nil
+}
+
+// ids parses the named path values, responding with 404 if any of them is invalid.
+func ids(w http.ResponseWriter, r *http.Request, names ...string) ([]int64, bool) {
+	res := make([]int64, len(names))
+	for i, name := range names {
+		id, ok := pathID(r, name)
+		if !ok {
+			notFound(w)
+			return nil, false
+		}
+		res[i] = id
+	}
+	return res, true
+}
+
+func queryBool(r *http.Request, key string) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get(key))
+	return v
+}
+
+func orderBy[T any](items []T, desc bool, key func(T) float64) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		if desc {
+			return cmp.Compare(key(b), key(a))
+		}
+		return cmp.Compare(key(a), key(b))
+	})
+}
+
+func page[T any](items []T, r *http.Request) []T {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset = min(max(offset, 0), len(items))
+	if err != nil || limit <= 0 {
+		return items[offset:]
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/ping", func(w http.ResponseWriter, r *http.Request) {})
+	s.installationRoutes(mux)
+	s.functionRoutes(mux)
+	s.deviceRoutes(mux)
+	s.organizationRoutes(mux)
+	s.userRoutes(mux)
+	s.scheduleRoutes(mux)
+	s.notificationRoutes(mux)
+	s.fileRoutes(mux)
+	s.edgeAppRoutes(mux)
+	s.traceRoutes(mux)
+	s.logRoutes(mux)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery})
+		mux.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,415 @@
+package lynxtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+func TestServer_Functions(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	seeded := srv.AddFunction(&lynx.Function{InstallationID: 1, Type: "temperature", Meta: lynx.Meta{"name": "a"}})
+	srv.AddFunction(&lynx.Function{InstallationID: 2, Type: "temperature"})
+
+	created, err := c.CreateFunction(&lynx.Function{InstallationID: 1, Type: "switch", Meta: lynx.Meta{"name": "b"}})
+	if err != nil {
+		t.Fatalf("CreateFunction() error = %v", err)
+	}
+	list, err := c.GetFunctions(1, lynx.Filter{"type": "switch"})
+	if err != nil || len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("GetFunctions() = %v, %v", list, err)
+	}
+	if _, err := c.CreateFunctionMeta(1, seeded.ID, "secret", lynx.MetaObject{Value: "x", Protected: true}, false); err != nil {
+		t.Fatalf("CreateFunctionMeta() error = %v", err)
+	}
+	fn, err := c.GetFunction(1, seeded.ID)
+	if err != nil || fn.ProtectedMeta["secret"] != "x" {
+		t.Fatalf("GetFunction() = %v, %v", fn, err)
+	}
+	if err := c.DeleteFunction(created); err != nil {
+		t.Fatalf("DeleteFunction() error = %v", err)
+	}
+	if _, err := c.GetFunction(1, created.ID); !errors.Is(err, lynx.ErrNotFound) {
+		t.Errorf("GetFunction() after delete error = %v, want ErrNotFound", err)
+	}
+	if got := srv.Functions(1); len(got) != 1 {
+		t.Errorf("Functions() = %v", got)
+	}
+}
+
+func TestServer_Files(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	f, err := c.CreateFileInstallation(1, "data.txt", "text/plain", bytes.NewBufferString("hello"))
+	if err != nil {
+		t.Fatalf("CreateFileInstallation() error = %v", err)
+	}
+	if f.Name != "data.txt" || f.MIME != "text/plain" {
+		t.Errorf("CreateFileInstallation() = %+v", f)
+	}
+	r, err := c.DownloadFile(f.Hash)
+	if err != nil {
+		t.Fatalf("DownloadFile() error = %v", err)
+	}
+	defer r.Close()
+	if content, _ := io.ReadAll(r); string(content) != "hello" {
+		t.Errorf("DownloadFile() = %s", content)
+	}
+}
+
+func TestServer_Log(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	now := float64(time.Now().Unix())
+	srv.AddLog(1,
+		lynx.LogEntry{Topic: "obj/a", Value: 1, Timestamp: now - 10},
+		lynx.LogEntry{Topic: "obj/a", Value: 2, Timestamp: now - 5},
+		lynx.LogEntry{Topic: "obj/b", Value: 3, Timestamp: now - 1},
+	)
+	status, err := c.Status(1, nil)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if m := status.Map(); len(m) != 2 || m["obj/a"].Value != 2 {
+		t.Errorf("Status() = %v", m)
+	}
+	log, err := c.V3().Log(1, nil)
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if log.Total != 3 || log.Data[0].Value != 3 {
+		t.Errorf("Log() = %+v", log)
+	}
+}
+
+func TestServer_Ping(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	if err := srv.Client().Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if r := srv.Requests(); len(r) != 1 || r[0].Path != "/api/v2/ping" {
+		t.Errorf("Requests() = %+v, want /api/v2/ping", r)
+	}
+}
+
+func TestServer_Devices(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	seeded := srv.AddDevice(&lynx.Device{InstallationID: 1, Type: "sensor"})
+	srv.AddDevice(&lynx.Device{InstallationID: 2, Type: "sensor"})
+	created, err := c.CreateDevice(&lynx.Device{InstallationID: 1, Type: "gateway", Meta: lynx.Meta{"name": "b"}})
+	if err != nil {
+		t.Fatalf("CreateDevice() error = %v", err)
+	}
+	list, err := c.GetDevices(1, lynx.Filter{"name": "b"})
+	if err != nil || len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("GetDevices() = %v, %v", list, err)
+	}
+	seeded.Type = "meter"
+	if _, err := c.UpdateDevice(seeded); err != nil {
+		t.Fatalf("UpdateDevice() error = %v", err)
+	}
+	if dev, err := c.GetDevice(1, seeded.ID); err != nil || dev.Type != "meter" {
+		t.Errorf("GetDevice() = %v, %v", dev, err)
+	}
+	if _, err := c.GetDevice(2, seeded.ID); !errors.Is(err, lynx.ErrNotFound) {
+		t.Errorf("GetDevice() in other installation error = %v, want ErrNotFound", err)
+	}
+	if err := c.DeleteDevice(created); err != nil {
+		t.Fatalf("DeleteDevice() error = %v", err)
+	}
+	if got := srv.Devices(1); len(got) != 1 || got[0].ID != seeded.ID {
+		t.Errorf("Devices() = %v", got)
+	}
+}
+
+func TestServer_Installations(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	i := srv.AddInstallation(&lynx.InstallationRow{ClientID: 10, Name: "home", OrganizationID: 3, Meta: lynx.Meta{"site": "a"}})
+	srv.AddInstallation(&lynx.InstallationRow{ClientID: 11, OrganizationID: 4})
+	rows, err := c.ListInstallations(lynx.Filter{"site": "a"})
+	if err != nil || len(rows) != 1 || rows[0].ID != i.ID {
+		t.Fatalf("ListInstallations() = %v, %v", rows, err)
+	}
+	i.Name = "office"
+	if _, err := c.UpdateInstallation(i); err != nil {
+		t.Fatalf("UpdateInstallation() error = %v", err)
+	}
+	if row, err := c.GetInstallationRow(i.ID); err != nil || row.Name != "office" {
+		t.Errorf("GetInstallationRow() = %v, %v", row, err)
+	}
+
+	infos, err := c.GetInstallations(false)
+	if err != nil || len(infos) != 2 {
+		t.Fatalf("GetInstallations() = %v, %v", infos, err)
+	}
+	info, err := c.GetInstallationByClientID(10, false)
+	if err != nil || info.ID != i.ID || info.Name != "office" || info.OrganizationID != 3 {
+		t.Errorf("GetInstallationByClientID() = %+v, %v", info, err)
+	}
+	if _, err := c.GetInstallationByClientID(99, false); !errors.Is(err, lynx.ErrNotFound) {
+		t.Errorf("GetInstallationByClientID() unknown error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := c.CreateInstallationMeta(i.ID, "key", lynx.MetaObject{Value: "v"}, false); err != nil {
+		t.Fatalf("CreateInstallationMeta() error = %v", err)
+	}
+	if m, err := c.GetInstallationMeta(i.ID, "key"); err != nil || m.Value != "v" {
+		t.Errorf("GetInstallationMeta() = %v, %v", m, err)
+	}
+}
+
+func TestServer_Organizations(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	org, err := c.CreateOrganization(&lynx.Organization{Name: "acme", Meta: lynx.Meta{"tier": "gold"}})
+	if err != nil {
+		t.Fatalf("CreateOrganization() error = %v", err)
+	}
+	srv.AddOrganization(&lynx.Organization{Name: "other"})
+	list, err := c.ListOrganization(true, lynx.Filter{"tier": "gold"})
+	if err != nil || len(list) != 1 || list[0].ID != org.ID {
+		t.Fatalf("ListOrganization() = %v, %v", list, err)
+	}
+	org.Name = "acme inc"
+	if _, err := c.UpdateOrganization(org); err != nil {
+		t.Fatalf("UpdateOrganization() error = %v", err)
+	}
+	if got, err := c.GetOrganization(org.ID); err != nil || got.Name != "acme inc" {
+		t.Errorf("GetOrganization() = %v, %v", got, err)
+	}
+	if err := c.ForcePasswordReset(org.ID); err != nil {
+		t.Errorf("ForcePasswordReset() error = %v", err)
+	}
+	if err := c.DeleteOrganization(org, true); err != nil {
+		t.Fatalf("DeleteOrganization() error = %v", err)
+	}
+	requests := srv.Requests()
+	if last := requests[len(requests)-1]; last.Path != fmt.Sprintf("/api/v2/organization/%d", org.ID) || last.Query != "force=true" {
+		t.Errorf("DeleteOrganization() request = %+v", last)
+	}
+	if got := srv.Organizations(); len(got) != 1 || got[0].Name != "other" {
+		t.Errorf("Organizations() = %v", got)
+	}
+}
+
+func TestServer_Users(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	if _, err := c.Me(); err == nil {
+		t.Errorf("Me() without SetMe error = nil")
+	}
+	me := srv.SetMe(&lynx.User{Email: "me@example.com"})
+	other := srv.AddUser(&lynx.User{Email: "other@example.com", Meta: lynx.Meta{"team": "a"}})
+	if u, err := c.Me(); err != nil || u.ID != me.ID {
+		t.Fatalf("Me() = %v, %v", u, err)
+	}
+	me.FirstName = "Ada"
+	if _, err := c.UpdateMe(me); err != nil {
+		t.Fatalf("UpdateMe() error = %v", err)
+	}
+	other.FirstName = "Bob"
+	if _, err := c.UpdateUser(other); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+	users, err := c.GetUsers(lynx.Filter{"team": "a"})
+	if err != nil || len(users) != 1 || users[0].FirstName != "Bob" {
+		t.Errorf("GetUsers() = %v, %v", users, err)
+	}
+	if got := srv.Users(); len(got) != 2 || got[0].FirstName != "Ada" {
+		t.Errorf("Users() = %v", got)
+	}
+}
+
+func TestServer_Schedules(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	created, err := c.CreateSchedule(&lynx.Schedule{InstallationID: 1, Executor: "timer", Topic: "set/a"})
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+	srv.AddSchedule(&lynx.Schedule{InstallationID: 1, Executor: "sun"})
+	list, err := c.GetSchedules(1, "timer")
+	if err != nil || len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("GetSchedules() = %v, %v", list, err)
+	}
+	created.Active = true
+	if _, err := c.UpdateSchedule(created); err != nil {
+		t.Fatalf("UpdateSchedule() error = %v", err)
+	}
+	if sc, err := c.GetSchedule(1, created.ID); err != nil || !sc.Active {
+		t.Errorf("GetSchedule() = %v, %v", sc, err)
+	}
+	if err := c.DeleteSchedule(created); err != nil {
+		t.Fatalf("DeleteSchedule() error = %v", err)
+	}
+	if got := srv.Schedules(1); len(got) != 1 || got[0].Executor != "sun" {
+		t.Errorf("Schedules() = %v", got)
+	}
+}
+
+func TestServer_Notifications(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	executor := srv.AddNotificationOutputExecutor(&lynx.NotificationOutputExecutor{Type: "email"})
+	msg, err := c.CreateNotificationMessage(1, &lynx.NotificationMessage{Name: "alarm", Text: "{{value}}"})
+	if err != nil {
+		t.Fatalf("CreateNotificationMessage() error = %v", err)
+	}
+	msg.Text = "alarm {{value}}"
+	if _, err := c.UpdateNotificationMessage(1, msg); err != nil {
+		t.Fatalf("UpdateNotificationMessage() error = %v", err)
+	}
+	if got, err := c.GetNotificationMessage(1, msg.ID); err != nil || got.Text != msg.Text {
+		t.Errorf("GetNotificationMessage() = %v, %v", got, err)
+	}
+	if _, err := c.GetNotificationMessage(2, msg.ID); !errors.Is(err, lynx.ErrNotFound) {
+		t.Errorf("GetNotificationMessage() in other installation error = %v, want ErrNotFound", err)
+	}
+
+	output, err := c.CreateNotificationOutput(&lynx.NotificationOutput{
+		InstallationID:               1,
+		NotificationOutputExecutorID: executor.ID,
+		NotificationMessageID:        msg.ID,
+	})
+	if err != nil {
+		t.Fatalf("CreateNotificationOutput() error = %v", err)
+	}
+	output.Name = "mail"
+	if _, err := c.UpdateNotificationOutput(output); err != nil {
+		t.Fatalf("UpdateNotificationOutput() error = %v", err)
+	}
+	if outputs, err := c.GetNotificationOutputs(1); err != nil || len(outputs) != 1 || outputs[0].Name != "mail" {
+		t.Errorf("GetNotificationOutputs() = %v, %v", outputs, err)
+	}
+	if got, err := c.GetNotificationOutputExecutor(1, executor.ID); err != nil || got.Type != "email" {
+		t.Errorf("GetNotificationOutputExecutor() = %v, %v", got, err)
+	}
+	if err := c.SendNotification(1, output.ID, map[string]any{"value": 1}); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+	if n := srv.Notifications(); len(n) != 1 || n[0].OutputID != output.ID || string(n[0].Data) != `{"value":1}` {
+		t.Errorf("Notifications() = %+v", n)
+	}
+
+	if err := c.DeleteNotificationOutput(output); err != nil {
+		t.Fatalf("DeleteNotificationOutput() error = %v", err)
+	}
+	if err := c.DeleteNotificationMessage(1, msg); err != nil {
+		t.Fatalf("DeleteNotificationMessage() error = %v", err)
+	}
+	if msgs, err := c.GetNotificationMessages(1); err != nil || len(msgs) != 0 {
+		t.Errorf("GetNotificationMessages() after delete = %v, %v", msgs, err)
+	}
+}
+
+func TestServer_EdgeApps(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	app, err := c.CreateEdgeApp(&lynx.EdgeApp{Name: "app", Publisher: &lynx.Publisher{ID: 3}})
+	if err != nil {
+		t.Fatalf("CreateEdgeApp() error = %v", err)
+	}
+	srv.AddEdgeApp(&lynx.EdgeApp{Name: "public", Public: true})
+	if apps, err := c.GetEdgeAppsOrganization(3, false); err != nil || len(apps) != 1 || apps[0].ID != app.ID {
+		t.Errorf("GetEdgeAppsOrganization() = %v, %v", apps, err)
+	}
+	if apps, err := c.GetEdgeAppsOrganization(3, true); err != nil || len(apps) != 2 {
+		t.Errorf("GetEdgeAppsOrganization() with available = %v, %v", apps, err)
+	}
+
+	hash, err := c.CreateEdgeAppVersion(app.ID, bytes.NewBufferString("-- lua"), bytes.NewBufferString(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("CreateEdgeAppVersion() error = %v", err)
+	}
+	if versions, err := c.GetEdgeAppVersions(app.ID, true); err != nil || len(versions) != 1 || versions[0].Hash != hash {
+		t.Errorf("GetEdgeAppVersions() = %v, %v", versions, err)
+	}
+	if versions, err := c.GetEdgeAppVersions(app.ID, false); err != nil || len(versions) != 0 {
+		t.Errorf("GetEdgeAppVersions() without untagged = %v, %v", versions, err)
+	}
+	if options, err := c.GetEdgeAppConfigOptions(app.ID, hash); err != nil || string(options) != `{"a":1}` {
+		t.Errorf("GetEdgeAppConfigOptions() = %s, %v", options, err)
+	}
+
+	instance, err := c.CreateEdgeAppInstance(&lynx.EdgeAppConfig{AppID: app.ID, InstallationID: 1, Version: hash})
+	if err != nil {
+		t.Fatalf("CreateEdgeAppInstance() error = %v", err)
+	}
+	instance.Name = "instance"
+	if _, err := c.UpdateEdgeAppInstance(instance); err != nil {
+		t.Fatalf("UpdateEdgeAppInstance() error = %v", err)
+	}
+	if got, err := c.GetEdgeAppInstance(1, instance.ID); err != nil || got.Name != "instance" {
+		t.Errorf("GetEdgeAppInstance() = %v, %v", got, err)
+	}
+	if configured, err := c.GetConfiguredEdgeApps(1); err != nil || len(configured) != 1 {
+		t.Errorf("GetConfiguredEdgeApps() = %v, %v", configured, err)
+	}
+	if err := c.DeleteEdgeAppInstance(instance); err != nil {
+		t.Fatalf("DeleteEdgeAppInstance() error = %v", err)
+	}
+	if got := srv.EdgeAppInstances(1); len(got) != 0 {
+		t.Errorf("EdgeAppInstances() after delete = %v", got)
+	}
+}
+
+func TestServer_Traces(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	now := time.Now().Unix()
+	srv.AddTrace(
+		lynx.TraceEntry{ID: "a", Timestamp: float64(now - 30), ObjectType: lynx.TraceObjectTypeFunction, ObjectID: 1},
+		lynx.TraceEntry{ID: "b", Timestamp: float64(now - 20), ObjectType: lynx.TraceObjectTypeDevice, ObjectID: 2},
+		lynx.TraceEntry{ID: "c", Timestamp: float64(now - 10), ObjectType: lynx.TraceObjectTypeFunction, ObjectID: 1},
+		lynx.TraceEntry{ID: "d", Timestamp: float64(now - 3600)},
+	)
+	opts := &lynx.TraceOptions{From: time.Unix(now-60, 0), To: time.Unix(now, 0), Limit: 10}
+	page, err := c.GetTraces(opts)
+	if err != nil || page.Total != 3 || page.Data[0].ID != "c" {
+		t.Fatalf("GetTraces() = %+v, %v", page, err)
+	}
+	opts.ObjectType, opts.ObjectID, opts.Order = lynx.TraceObjectTypeFunction, 1, lynx.LogOrderAsc
+	page, err = c.GetTraces(opts)
+	if err != nil || page.Total != 2 || page.Data[0].ID != "a" || page.LastTime != float64(now-10) {
+		t.Errorf("GetTraces() by object = %+v, %v", page, err)
+	}
+	opts.ObjectType, opts.ID = lynx.TraceObjectTypeNone, "b"
+	page, err = c.GetTraces(opts)
+	if err != nil || page.Total != 1 || page.Data[0].ID != "b" {
+		t.Errorf("GetTraces() by id = %+v, %v", page, err)
+	}
+}
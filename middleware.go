@@ -0,0 +1,66 @@
+package lynx
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RoundTripFunc performs a single HTTP request
+type RoundTripFunc func(r *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc, for example to inspect or modify requests and responses.
+// Middlewares are called for every attempt, so a retried request passes through them several times.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+func chainMiddleware(middleware []Middleware, rt RoundTripFunc) RoundTripFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		rt = middleware[i](rt)
+	}
+	return rt
+}
+
+// SlogMiddleware logs every request with method, path, status and duration to logger.
+// Failed requests are logged at error level, requests with a non 2xx status at warn level.
+func SlogMiddleware(logger *slog.Logger) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			response, err := next(r)
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(r.Context(), slog.LevelError, "lynx request failed", attrs...)
+				return response, err
+			}
+			attrs = append(attrs, slog.Int("status", response.StatusCode))
+			level := slog.LevelDebug
+			if response.StatusCode >= http.StatusMultipleChoices {
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(r.Context(), level, "lynx request", attrs...)
+			return response, err
+		}
+	}
+}
+
+// LatencyMiddleware calls observe with the duration of every request, suitable for feeding a histogram.
+// status is 0 if the request failed without a response.
+func LatencyMiddleware(observe func(method, path string, status int, duration time.Duration)) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			response, err := next(r)
+			status := 0
+			if response != nil {
+				status = response.StatusCode
+			}
+			observe(r.Method, r.URL.Path, status, time.Since(start))
+			return response, err
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package lynx
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"net/http"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -28,15 +32,89 @@ func (m *Message) Time() time.Time {
 	return time.Unix(int64(whole), int64(fractals*1000000000))
 }
 
+// ErrTimeout is matched by errors.Is for MQTT operations that did not complete in time
+var ErrTimeout = errors.New("timeout")
+
+// defaultMqttTimeout is the timeout for publish, subscribe and unsubscribe if Options.MqttTimeout is not set
+const defaultMqttTimeout = time.Second
+
+// TimeoutError is returned when an MQTT operation is not acknowledged before the deadline.
+// It matches ErrTimeout and context.DeadlineExceeded with errors.Is, errors reported by
+// the broker are returned as is.
+type TimeoutError struct {
+	// Op is the operation that timed out, one of connect, publish, subscribe and unsubscribe
+	Op    string
+	Topic string
+}
+
+func (e *TimeoutError) Error() string {
+	switch e.Op {
+	case "connect":
+		return "connection timeout"
+	case "subscribe":
+		return fmt.Sprintf("timeout subscribing to topic %s", e.Topic)
+	case "unsubscribe":
+		return fmt.Sprintf("timeout unsubscribing from topic %s", e.Topic)
+	default:
+		return fmt.Sprintf("timeout publishing to topic %s", e.Topic)
+	}
+}
+
+// Timeout reports true, like net.Error
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout || target == context.DeadlineExceeded
+}
+
+// waitToken waits for token to complete or ctx to be done. It returns a *TimeoutError if
+// the deadline of ctx is exceeded, ctx.Err() if it is cancelled and the token error otherwise.
+func waitToken(ctx context.Context, token mqtt.Token, op, topic string) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return contextError(ctx, op, topic)
+	}
+}
+
+// contextError returns a *TimeoutError if the deadline of ctx is exceeded, otherwise ctx.Err().
+func contextError(ctx context.Context, op, topic string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Op: op, Topic: topic}
+	}
+	return ctx.Err()
+}
+
+// mqttContext returns a context with the timeout for publish, subscribe and unsubscribe.
+func (c *Client) mqttContext() (context.Context, context.CancelFunc) {
+	timeout := c.opt.MqttTimeout
+	if timeout <= 0 {
+		timeout = defaultMqttTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// MQTTConnect connects to the broker, waiting at most Options.MqttConnectTimeout,
+// which defaults to the connect timeout of Options.MqttOptions.
 func (c *Client) MQTTConnect() error {
-	x := c.Mqtt.Connect()
-	timedOut := x.WaitTimeout(time.Second)
-	if x.Error() != nil {
-		return x.Error()
-	} else if !timedOut {
-		return fmt.Errorf("connection timeout")
+	timeout := c.opt.MqttConnectTimeout
+	if timeout <= 0 && c.opt.MqttOptions != nil {
+		timeout = c.opt.MqttOptions.ConnectTimeout
 	}
-	return nil
+	if timeout <= 0 {
+		timeout = time.Second * 30
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.MQTTConnectContext(ctx)
+}
+
+// MQTTConnectContext connects to the broker, waiting until the connection is established or ctx is done.
+func (c *Client) MQTTConnectContext(ctx context.Context) error {
+	return waitToken(ctx, c.Mqtt.Connect(), "connect", "")
 }
 
 func (c *Client) MQTTDisconnect() {
@@ -46,65 +124,171 @@ func (c *Client) MQTTDisconnect() {
 // PublishAllTimeout publishes all messages in the provided slice to their respective topics with a specified timeout.
 // It returns a slice of errors for any messages that failed to publish within the timeout.
 func (c *Client) PublishAllTimeout(messages []MQTTMessage, timeout time.Duration) []error {
-	var queue []mqtt.Token
-	var topic []string
-	var errors []error
-	for _, msg := range messages {
-		data, _ := json.Marshal(msg.Msg)
-		token := c.Mqtt.Publish(msg.Topic, msg.QoS, false, data)
-		queue = append(queue, token)
-		topic = append(topic, msg.Topic)
-	}
-	for i, token := range queue {
-		ok := token.WaitTimeout(timeout)
-		if !ok {
-			errors = append(errors, fmt.Errorf("timeout publishing to topic %s", topic[i]))
-		} else if err := token.Error(); err != nil {
-			errors = append(errors, fmt.Errorf("error publishing to topic %s: %w", topic[i], err))
-		}
-	}
-	return errors
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.PublishAllContext(ctx, messages)
 }
 
 // PublishAll publishes all messages in the provided slice to their respective topics.
-// It returns a slice of errors for any messages that failed to publish.
+// It returns a slice of errors for any messages that failed to publish. If Options.MqttTimeout
+// is set each message is waited for at most that long, otherwise until it is acknowledged.
 func (c *Client) PublishAll(messages []MQTTMessage) []error {
-	var queue []mqtt.Token
-	var topic []string
-	var errors []error
-	for _, msg := range messages {
+	return c.publishAll(context.Background(), messages, c.opt.MqttTimeout)
+}
+
+// PublishAllContext is like PublishAll but waits for the messages to be published until ctx is done.
+func (c *Client) PublishAllContext(ctx context.Context, messages []MQTTMessage) []error {
+	return c.publishAll(ctx, messages, 0)
+}
+
+// publishAll publishes messages until ctx is done, waiting at most timeout for each message if it is set.
+func (c *Client) publishAll(ctx context.Context, messages []MQTTMessage, timeout time.Duration) []error {
+	messageContext := func() (context.Context, context.CancelFunc) {
+		if timeout > 0 {
+			return context.WithTimeout(ctx, timeout)
+		}
+		return ctx, func() {}
+	}
+	if c.opt.Outbox != nil {
+		return c.publishAllOutbox(messageContext, messages)
+	}
+	queue := make([]mqtt.Token, len(messages))
+	var errs []error
+	for i, msg := range messages {
+		if ctx.Err() != nil {
+			continue
+		}
 		data, _ := json.Marshal(msg.Msg)
-		token := c.Mqtt.Publish(msg.Topic, msg.QoS, false, data)
-		queue = append(queue, token)
-		topic = append(topic, msg.Topic)
+		queue[i] = c.Mqtt.Publish(msg.Topic, msg.QoS, false, data)
 	}
 	for i, token := range queue {
-		ok := token.Wait()
-		if !ok {
-			errors = append(errors, fmt.Errorf("timeout publishing to topic %s", topic[i]))
-		} else if err := token.Error(); err != nil {
-			errors = append(errors, fmt.Errorf("error publishing to topic %s: %w", topic[i], err))
+		topic := messages[i].Topic
+		if token == nil {
+			errs = append(errs, contextError(ctx, "publish", topic))
+			continue
+		}
+		msgCtx, cancel := messageContext()
+		err := waitToken(msgCtx, token, "publish", topic)
+		cancel()
+		if err != nil {
+			errs = append(errs, publishError(topic, err))
 		}
 	}
-	return errors
+	return errs
 }
 
 // Publish publishes a message to the specified topic with the given QoS level.
-// It marshals the payload into JSON format before sending.
+// It marshals the payload into JSON format before sending and waits at most Options.MqttTimeout.
+// If Options.Outbox is set, messages that can not be published are queued and published on reconnect.
 func (c *Client) Publish(topic string, payload interface{}, qos byte) error {
+	ctx, cancel := c.mqttContext()
+	defer cancel()
+	return c.PublishContext(ctx, topic, payload, qos)
+}
+
+// PublishContext is like Publish but waits for the message to be published until ctx is done.
+func (c *Client) PublishContext(ctx context.Context, topic string, payload interface{}, qos byte) error {
 	data, _ := json.Marshal(payload)
-	token := c.Mqtt.Publish(topic, qos, false, data)
-	token.WaitTimeout(time.Second)
-	return token.Error()
+	rec := outboxRecord{Topic: topic, QoS: qos, Payload: data}
+	if c.opt.Outbox != nil {
+		return c.publishOutbox(ctx, rec)
+	}
+	return c.publishRecord(ctx, rec)
+}
+
+// publishError wraps errors reported by the broker, timeouts and cancellations are returned as is.
+func publishError(topic string, err error) error {
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) || errors.Is(err, context.Canceled) {
+		return err
+	}
+	return fmt.Errorf("error publishing to topic %s: %w", topic, err)
+}
+
+func (c *Client) publishRecord(ctx context.Context, rec outboxRecord) error {
+	if ctx.Err() != nil {
+		return contextError(ctx, "publish", rec.Topic)
+	}
+	token := c.Mqtt.Publish(rec.Topic, rec.QoS, false, []byte(rec.Payload))
+	if err := waitToken(ctx, token, "publish", rec.Topic); err != nil {
+		return publishError(rec.Topic, err)
+	}
+	return nil
+}
+
+// publishOutbox publishes the message directly if connected and nothing is queued,
+// otherwise it is queued in the outbox, which is flushed if connected.
+// A message handed to the MQTT client is never queued, since the client may still deliver
+// it after a timeout, so those errors are returned instead.
+func (c *Client) publishOutbox(ctx context.Context, rec outboxRecord) error {
+	if ctx.Err() != nil {
+		return contextError(ctx, "publish", rec.Topic)
+	}
+	if c.Mqtt.IsConnectionOpen() && c.opt.Outbox.Len() == 0 {
+		err := c.publishRecord(ctx, rec)
+		if !errors.Is(err, mqtt.ErrNotConnected) {
+			return err
+		}
+	}
+	if err := c.opt.Outbox.push(rec); err != nil {
+		return fmt.Errorf("error queueing message to topic %s: %w", rec.Topic, err)
+	}
+	if c.Mqtt.IsConnectionOpen() {
+		c.flushOutbox()
+	}
+	return nil
+}
+
+func (c *Client) publishAllOutbox(messageContext func() (context.Context, context.CancelFunc), messages []MQTTMessage) []error {
+	var errs []error
+	for _, msg := range messages {
+		data, _ := json.Marshal(msg.Msg)
+		ctx, cancel := messageContext()
+		err := c.publishOutbox(ctx, outboxRecord{Topic: msg.Topic, QoS: msg.QoS, Payload: data})
+		cancel()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// flushOutbox publishes the messages queued in the outbox, stopping at the first failure.
+func (c *Client) flushOutbox() {
+	publish := func(rec outboxRecord) error {
+		ctx, cancel := c.mqttContext()
+		defer cancel()
+		return c.publishRecord(ctx, rec)
+	}
+	if err := c.opt.Outbox.flush(publish); err != nil {
+		log.Println("MQTT: outbox flush stopped:", err.Error())
+	}
 }
 
 // NewMqttOptions returns default mqtt configuration
 // conf is a subset of a viper config which can include:
-// broker, the MQTT broker URI
+// broker, the MQTT broker URI, tcp://, ssl://, ws:// and wss:// are supported
 // client_id, id to be used by the client
 // connection_log, boolean value for enabling/disabling connection logging
 // timeout, the connect-timeout to be used on the client. Defaults to 30s
+// keepalive, the keepalive interval. Defaults to 30s
+// clean_session, boolean value for clean sessions. Defaults to true
+// store, directory for persisting in-flight messages. Defaults to memory
+// headers, map of HTTP headers sent when connecting over websockets
+// tls.ca_file, tls.cert_file, tls.key_file, tls.min_version, tls.server_name and tls.insecure_skip_verify
+// will.topic, will.payload, will.qos and will.retained, the last will message
+// Invalid settings are logged and ignored, use MqttOptionsFromConfig to get the error.
 func NewMqttOptions(conf *viper.Viper, onConnect mqtt.OnConnectHandler, onLost mqtt.ConnectionLostHandler) *mqtt.ClientOptions {
+	opts, err := MqttOptionsFromConfig(conf, onConnect, onLost)
+	if err != nil {
+		log.Println("MQTT: invalid configuration:", err.Error())
+	}
+	return opts
+}
+
+// MqttOptionsFromConfig is like NewMqttOptions but returns an error for invalid settings.
+// The returned options are usable without the invalid settings.
+func MqttOptionsFromConfig(conf *viper.Viper, onConnect mqtt.OnConnectHandler, onLost mqtt.ConnectionLostHandler) (*mqtt.ClientOptions, error) {
 	opts := mqtt.NewClientOptions()
 	opts.AddBroker(conf.GetString("broker"))
 	opts.SetClientID(conf.GetString("client_id"))
@@ -117,6 +301,39 @@ func NewMqttOptions(conf *viper.Viper, onConnect mqtt.OnConnectHandler, onLost m
 	} else {
 		opts.SetConnectTimeout(time.Second * 30)
 	}
+	if conf.IsSet("keepalive") {
+		opts.SetKeepAlive(conf.GetDuration("keepalive"))
+	}
+	if conf.IsSet("clean_session") {
+		opts.SetCleanSession(conf.GetBool("clean_session"))
+	}
+	if store := conf.GetString("store"); store != "" {
+		opts.SetStore(mqtt.NewFileStore(store))
+	}
+	if headers := conf.GetStringMapString("headers"); len(headers) > 0 {
+		h := make(http.Header, len(headers))
+		for k, v := range headers {
+			h.Set(k, v)
+		}
+		opts.SetHTTPHeaders(h)
+	}
+	if topic := conf.GetString("will.topic"); topic != "" {
+		opts.SetWill(topic, conf.GetString("will.payload"), byte(conf.GetUint("will.qos")), conf.GetBool("will.retained"))
+	}
+
+	var err error
+	if conf.IsSet("tls") {
+		var tlsOptions *TLSOptions
+		var config *tls.Config
+		if tlsOptions, err = tlsOptionsFromConfig(conf); err == nil {
+			config, err = tlsOptions.Config()
+		}
+		if err != nil {
+			err = fmt.Errorf("tls: %w", err)
+		} else {
+			opts.SetTLSConfig(config)
+		}
+	}
 
 	opts.SetConnectionLostHandler(func(c mqtt.Client, err error) {
 		if conf.GetBool("connection_log") {
@@ -134,5 +351,5 @@ func NewMqttOptions(conf *viper.Viper, onConnect mqtt.OnConnectHandler, onLost m
 			onConnect(c)
 		}
 	})
-	return opts
+	return opts, err
 }
@@ -0,0 +1,179 @@
+package lynx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+	"github.com/spf13/viper"
+)
+
+func TestClient_MQTTConnectContext(t *testing.T) {
+	// A listener that accepts connections but never answers CONNECT
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	b := lynxtest.NewTestBroker(t)
+	opts := b.MqttOptions("test", nil, nil)
+	opts.Servers = nil
+	opts.AddBroker("tcp://" + l.Addr().String())
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, MqttOptions: opts})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	err = c.MQTTConnectContext(ctx)
+	if !errors.Is(err, lynx.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("MQTTConnectContext() error = %v, want timeout", err)
+	}
+	var timeout *lynx.TimeoutError
+	if !errors.As(err, &timeout) || timeout.Op != "connect" {
+		t.Errorf("MQTTConnectContext() error = %#v, want *TimeoutError for connect", err)
+	}
+
+	c = lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, MqttOptions: opts, MqttConnectTimeout: time.Millisecond * 100})
+	if err := c.MQTTConnect(); !errors.Is(err, lynx.ErrTimeout) {
+		t.Errorf("MQTTConnect() error = %v, want timeout", err)
+	}
+}
+
+func TestClient_PublishContext(t *testing.T) {
+	b := lynxtest.NewTestBroker(t)
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, MqttOptions: b.MqttOptions("test", nil, nil)})
+
+	// Not connected is reported by the client, not as a timeout
+	err := c.PublishContext(context.Background(), "obj/value", lynx.Message{Value: 1}, 1)
+	if err == nil || errors.Is(err, lynx.ErrTimeout) {
+		t.Fatalf("PublishContext() while disconnected error = %v, want non-timeout error", err)
+	}
+
+	if err := c.MQTTConnectContext(context.Background()); err != nil {
+		t.Fatalf("MQTTConnectContext() error = %v", err)
+	}
+	defer c.MQTTDisconnect()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.PublishContext(ctx, "obj/value", lynx.Message{Value: 1}, 1); err != nil {
+		t.Fatalf("PublishContext() error = %v", err)
+	}
+	errs := c.PublishAllContext(ctx, []lynx.MQTTMessage{
+		{Topic: "obj/a", QoS: 1, Msg: lynx.Message{Value: 2}},
+		{Topic: "obj/b", QoS: 0, Msg: lynx.Message{Value: 3}},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("PublishAllContext() errors = %v", errs)
+	}
+	if _, ok := b.WaitForPublish("obj/b", time.Second); !ok {
+		t.Errorf("message to obj/b not published")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = c.PublishAllContext(cancelled, []lynx.MQTTMessage{{Topic: "obj/c", QoS: 1}})
+	if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("PublishAllContext() with cancelled context errors = %v, want context.Canceled", errs)
+	}
+	if err := c.PublishContext(cancelled, "obj/c", lynx.Message{}, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("PublishContext() with cancelled context error = %v, want context.Canceled", err)
+	}
+	if _, ok := b.WaitForPublish("obj/c", time.Millisecond*100); ok {
+		t.Errorf("message published with cancelled context")
+	}
+
+	// Without MqttTimeout PublishAll waits until all messages are acknowledged
+	batch := make([]lynx.MQTTMessage, 100)
+	for i := range batch {
+		batch[i] = lynx.MQTTMessage{Topic: "obj/batch", QoS: 1, Msg: lynx.Message{Value: float64(i)}}
+	}
+	if errs := c.PublishAll(batch); len(errs) != 0 {
+		t.Errorf("PublishAll() errors = %v", errs)
+	}
+}
+
+func TestMqttOptionsFromConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := `
+broker: wss://broker.example.com/mqtt
+client_id: test
+keepalive: 45s
+clean_session: false
+store: ` + filepath.Join(dir, "store") + `
+headers:
+  X-Api-Key: secret
+tls:
+  ca_file: ` + caFile + `
+  server_name: broker.example.com
+  insecure_skip_verify: true
+will:
+  topic: 1/obj/status
+  payload: offline
+  qos: 1
+  retained: true
+`
+	conf := viper.New()
+	conf.SetConfigType("yaml")
+	if err := conf.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := lynx.MqttOptionsFromConfig(conf, nil, nil)
+	if err != nil {
+		t.Fatalf("MqttOptionsFromConfig() error = %v", err)
+	}
+	if len(opts.Servers) != 1 || opts.Servers[0].Scheme != "wss" {
+		t.Errorf("Servers = %v, want wss broker", opts.Servers)
+	}
+	if opts.HTTPHeaders.Get("X-Api-Key") != "secret" {
+		t.Errorf("HTTPHeaders = %v", opts.HTTPHeaders)
+	}
+	if opts.KeepAlive != 45 {
+		t.Errorf("KeepAlive = %d, want 45", opts.KeepAlive)
+	}
+	if opts.CleanSession {
+		t.Errorf("CleanSession = true, want false")
+	}
+	if opts.Store == nil {
+		t.Errorf("Store not set")
+	}
+	if !opts.WillEnabled || opts.WillTopic != "1/obj/status" || !bytes.Equal(opts.WillPayload, []byte("offline")) ||
+		opts.WillQos != 1 || !opts.WillRetained {
+		t.Errorf("will = %v %q %q %d %v", opts.WillEnabled, opts.WillTopic, opts.WillPayload, opts.WillQos, opts.WillRetained)
+	}
+	if opts.TLSConfig == nil || opts.TLSConfig.RootCAs == nil || opts.TLSConfig.ServerName != "broker.example.com" ||
+		!opts.TLSConfig.InsecureSkipVerify {
+		t.Errorf("TLSConfig = %+v", opts.TLSConfig)
+	}
+
+	conf.Set("tls.cert_file", filepath.Join(dir, "missing.pem"))
+	if _, err := lynx.MqttOptionsFromConfig(conf, nil, nil); err == nil {
+		t.Errorf("MqttOptionsFromConfig() with client certificate but no key error = nil")
+	}
+}
@@ -1,6 +1,7 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -39,9 +40,13 @@ type NotificationExecutorPayload struct {
 }
 
 func (c *Client) GetNotificationMessages(installationID int64) ([]*NotificationMessage, error) {
+	return c.GetNotificationMessagesContext(context.Background(), installationID)
+}
+
+func (c *Client) GetNotificationMessagesContext(ctx context.Context, installationID int64) ([]*NotificationMessage, error) {
 	res := make([]*NotificationMessage, 0, 20)
 	path := fmt.Sprintf("api/v2/notification/%d/message", installationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -49,9 +54,13 @@ func (c *Client) GetNotificationMessages(installationID int64) ([]*NotificationM
 }
 
 func (c *Client) GetNotificationMessage(installationID, messageID int64) (*NotificationMessage, error) {
+	return c.GetNotificationMessageContext(context.Background(), installationID, messageID)
+}
+
+func (c *Client) GetNotificationMessageContext(ctx context.Context, installationID, messageID int64) (*NotificationMessage, error) {
 	msg := &NotificationMessage{}
 	path := fmt.Sprintf("api/v2/notification/%d/message/%d", installationID, messageID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &msg); err != nil {
 		return nil, err
 	}
@@ -59,9 +68,13 @@ func (c *Client) GetNotificationMessage(installationID, messageID int64) (*Notif
 }
 
 func (c *Client) CreateNotificationMessage(installationID int64, message *NotificationMessage) (*NotificationMessage, error) {
+	return c.CreateNotificationMessageContext(context.Background(), installationID, message)
+}
+
+func (c *Client) CreateNotificationMessageContext(ctx context.Context, installationID int64, message *NotificationMessage) (*NotificationMessage, error) {
 	msg := &NotificationMessage{}
 	path := fmt.Sprintf("api/v2/notification/%d/message", installationID)
-	req := c.newRequest(http.MethodPost, path, requestBody(message))
+	req := c.newRequest(ctx, http.MethodPost, path, requestBody(message))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, msg); err != nil {
 		return nil, err
@@ -70,9 +83,13 @@ func (c *Client) CreateNotificationMessage(installationID int64, message *Notifi
 }
 
 func (c *Client) UpdateNotificationMessage(installationID int64, message *NotificationMessage) (*NotificationMessage, error) {
+	return c.UpdateNotificationMessageContext(context.Background(), installationID, message)
+}
+
+func (c *Client) UpdateNotificationMessageContext(ctx context.Context, installationID int64, message *NotificationMessage) (*NotificationMessage, error) {
 	msg := &NotificationMessage{}
 	path := fmt.Sprintf("api/v2/notification/%d/message/%d", installationID, message.ID)
-	req := c.newRequest(http.MethodPut, path, requestBody(message))
+	req := c.newRequest(ctx, http.MethodPut, path, requestBody(message))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, msg); err != nil {
 		return nil, err
@@ -81,15 +98,23 @@ func (c *Client) UpdateNotificationMessage(installationID int64, message *Notifi
 }
 
 func (c *Client) DeleteNotificationMessage(installationID int64, message *NotificationMessage) error {
+	return c.DeleteNotificationMessageContext(context.Background(), installationID, message)
+}
+
+func (c *Client) DeleteNotificationMessageContext(ctx context.Context, installationID int64, message *NotificationMessage) error {
 	path := fmt.Sprintf("api/v2/notification/%d/message/%d", installationID, message.ID)
-	req := c.newRequest(http.MethodDelete, path, nil)
+	req := c.newRequest(ctx, http.MethodDelete, path, nil)
 	return c.do(req, nil)
 }
 
 func (c *Client) GetNotificationOutputs(installationID int64) ([]*NotificationOutput, error) {
+	return c.GetNotificationOutputsContext(context.Background(), installationID)
+}
+
+func (c *Client) GetNotificationOutputsContext(ctx context.Context, installationID int64) ([]*NotificationOutput, error) {
 	res := make([]*NotificationOutput, 0, 20)
 	path := fmt.Sprintf("api/v2/notification/%d/output", installationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -97,9 +122,13 @@ func (c *Client) GetNotificationOutputs(installationID int64) ([]*NotificationOu
 }
 
 func (c *Client) GetNotificationOutput(installationID, outputID int64) (*NotificationOutput, error) {
+	return c.GetNotificationOutputContext(context.Background(), installationID, outputID)
+}
+
+func (c *Client) GetNotificationOutputContext(ctx context.Context, installationID, outputID int64) (*NotificationOutput, error) {
 	o := &NotificationOutput{}
 	path := fmt.Sprintf("api/v2/notification/%d/output/%d", installationID, outputID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, o); err != nil {
 		return nil, err
 	}
@@ -107,9 +136,13 @@ func (c *Client) GetNotificationOutput(installationID, outputID int64) (*Notific
 }
 
 func (c *Client) CreateNotificationOutput(output *NotificationOutput) (*NotificationOutput, error) {
+	return c.CreateNotificationOutputContext(context.Background(), output)
+}
+
+func (c *Client) CreateNotificationOutputContext(ctx context.Context, output *NotificationOutput) (*NotificationOutput, error) {
 	o := &NotificationOutput{}
 	path := fmt.Sprintf("api/v2/notification/%d/output", output.InstallationID)
-	req := c.newRequest(http.MethodPost, path, requestBody(output))
+	req := c.newRequest(ctx, http.MethodPost, path, requestBody(output))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, o); err != nil {
 		return nil, err
@@ -118,9 +151,13 @@ func (c *Client) CreateNotificationOutput(output *NotificationOutput) (*Notifica
 }
 
 func (c *Client) UpdateNotificationOutput(output *NotificationOutput) (*NotificationOutput, error) {
+	return c.UpdateNotificationOutputContext(context.Background(), output)
+}
+
+func (c *Client) UpdateNotificationOutputContext(ctx context.Context, output *NotificationOutput) (*NotificationOutput, error) {
 	o := &NotificationOutput{}
 	path := fmt.Sprintf("api/v2/notification/%d/output/%d", output.InstallationID, output.ID)
-	req := c.newRequest(http.MethodPut, path, requestBody(output))
+	req := c.newRequest(ctx, http.MethodPut, path, requestBody(output))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(req, o); err != nil {
 		return nil, err
@@ -129,15 +166,23 @@ func (c *Client) UpdateNotificationOutput(output *NotificationOutput) (*Notifica
 }
 
 func (c *Client) DeleteNotificationOutput(output *NotificationOutput) error {
+	return c.DeleteNotificationOutputContext(context.Background(), output)
+}
+
+func (c *Client) DeleteNotificationOutputContext(ctx context.Context, output *NotificationOutput) error {
 	path := fmt.Sprintf("api/v2/notification/%d/output/%d", output.InstallationID, output.ID)
-	req := c.newRequest(http.MethodDelete, path, nil)
+	req := c.newRequest(ctx, http.MethodDelete, path, nil)
 	return c.do(req, nil)
 }
 
 func (c *Client) GetNotificationOutputExecutors(installationID int64) ([]*NotificationOutputExecutor, error) {
+	return c.GetNotificationOutputExecutorsContext(context.Background(), installationID)
+}
+
+func (c *Client) GetNotificationOutputExecutorsContext(ctx context.Context, installationID int64) ([]*NotificationOutputExecutor, error) {
 	executors := make([]*NotificationOutputExecutor, 0, 5)
 	path := fmt.Sprintf("api/v2/notification/%d/executor", installationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, &executors); err != nil {
 		return nil, err
 	}
@@ -145,9 +190,13 @@ func (c *Client) GetNotificationOutputExecutors(installationID int64) ([]*Notifi
 }
 
 func (c *Client) GetNotificationOutputExecutor(installationID, executorID int64) (*NotificationOutputExecutor, error) {
+	return c.GetNotificationOutputExecutorContext(context.Background(), installationID, executorID)
+}
+
+func (c *Client) GetNotificationOutputExecutorContext(ctx context.Context, installationID, executorID int64) (*NotificationOutputExecutor, error) {
 	ex := &NotificationOutputExecutor{}
 	path := fmt.Sprintf("api/v2/notification/%d/executor/%d", installationID, executorID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, ex); err != nil {
 		return nil, err
 	}
@@ -155,8 +204,12 @@ func (c *Client) GetNotificationOutputExecutor(installationID, executorID int64)
 }
 
 func (c *Client) SendNotification(installationID, outputID int64, data interface{}) error {
+	return c.SendNotificationContext(context.Background(), installationID, outputID, data)
+}
+
+func (c *Client) SendNotificationContext(ctx context.Context, installationID, outputID int64, data interface{}) error {
 	path := fmt.Sprintf("api/v2/notification/%d/output/%d/send", installationID, outputID)
-	req := c.newRequest(http.MethodPost, path, requestBody(data))
+	req := c.newRequest(ctx, http.MethodPost, path, requestBody(data))
 	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	return c.do(req, nil)
 }
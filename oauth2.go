@@ -0,0 +1,107 @@
+package lynx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClientCredentials is a TokenSource performing the OAuth2 client credentials grant, for
+// services authenticating without a user.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// HTTPClient is used for the token requests, defaults to a client with a 30s timeout.
+	// RefreshingAuth blocks all requests while a token is fetched, so it should have a timeout.
+	HTTPClient *http.Client
+}
+
+// defaultTokenClient is the HTTP client used for token requests if ClientCredentials.HTTPClient is not set
+var defaultTokenClient = &http.Client{Timeout: time.Second * 30}
+
+// NewClientCredentialsAuth returns an Authentication using bearer tokens from the client credentials
+// grant. Tokens are cached until they are about to expire, and used as password for MQTT.
+func NewClientCredentialsAuth(tokenURL, clientID, clientSecret string, scopes ...string) *RefreshingAuth {
+	return NewRefreshingAuth(&ClientCredentials{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+	})
+}
+
+// TokenError is an error response from an OAuth2 token endpoint
+type TokenError struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *TokenError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth2: %s: %s (%d)", e.Code, e.Description, e.StatusCode)
+	}
+	return fmt.Sprintf("oauth2: %s (%d)", e.Code, e.StatusCode)
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Token requests a new access token from the token endpoint.
+func (c *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	client := c.HTTPClient
+	if client == nil {
+		client = defaultTokenClient
+	}
+	response, err := client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		e := &TokenError{StatusCode: response.StatusCode}
+		if json.Unmarshal(body, e) != nil || e.Code == "" {
+			e.Code = http.StatusText(response.StatusCode)
+		}
+		return nil, e
+	}
+	res := tokenResponse{}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("oauth2: invalid token response: %w", err)
+	}
+	if res.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: token response has no access_token")
+	}
+	if res.TokenType != "" && !strings.EqualFold(res.TokenType, "bearer") {
+		return nil, fmt.Errorf("oauth2: unsupported token type %s", res.TokenType)
+	}
+	token := &Token{AccessToken: res.AccessToken}
+	if res.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,77 @@
+package lynx_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+func TestClientCredentials(t *testing.T) {
+	var issued atomic.Int64
+	expiresIn := int64(3600)
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"unsupported_grant_type"}`))
+			return
+		}
+		if id != "service" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"bad secret"}`))
+			return
+		}
+		if r.PostForm.Get("scope") != "read write" {
+			t.Errorf("scope = %q", r.PostForm.Get("scope"))
+		}
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, issued.Add(1), expiresIn)
+	}))
+	defer tokens.Close()
+	var auth atomic.Value
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+	}))
+	defer api.Close()
+
+	c := lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.NewClientCredentialsAuth(tokens.URL, "service", "secret", "read", "write"),
+		APIBase:       api.URL,
+	})
+	for range 3 {
+		if err := c.Ping(); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	}
+	if n := issued.Load(); n != 1 || auth.Load() != "Bearer token-1" {
+		t.Errorf("tokens issued = %d, Authorization = %v, want a single cached token", n, auth.Load())
+	}
+
+	// Tokens about to expire are refreshed
+	expiresIn = 10
+	c = lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.NewClientCredentialsAuth(tokens.URL, "service", "secret", "read", "write"),
+		APIBase:       api.URL,
+	})
+	for range 2 {
+		if err := c.Ping(); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	}
+	if n := issued.Load(); n != 3 {
+		t.Errorf("tokens issued = %d, want 3", n)
+	}
+
+	c = lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.NewClientCredentialsAuth(tokens.URL, "service", "wrong"),
+		APIBase:       api.URL,
+	})
+	var tokenErr *lynx.TokenError
+	if err := c.Ping(); !errors.As(err, &tokenErr) || tokenErr.Code != "invalid_client" || tokenErr.StatusCode != 401 {
+		t.Errorf("Ping() with invalid client error = %v, want invalid_client", err)
+	}
+}
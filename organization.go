@@ -1,6 +1,7 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -36,10 +37,14 @@ type OrganizationChild struct {
 }
 
 func (c *Client) ListOrganization(minimal bool, filter Filter) ([]*Organization, error) {
+	return c.ListOrganizationContext(context.Background(), minimal, filter)
+}
+
+func (c *Client) ListOrganizationContext(ctx context.Context, minimal bool, filter Filter) ([]*Organization, error) {
 	res := make([]*Organization, 0, 5)
 	filter["minimal"] = fmt.Sprintf("%t", minimal)
 	query := filter.ToURLValues()
-	req := c.newRequest(http.MethodGet, fmt.Sprintf("api/v2/organization?%s", query.Encode()), nil)
+	req := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("api/v2/organization?%s", query.Encode()), nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -47,9 +52,13 @@ func (c *Client) ListOrganization(minimal bool, filter Filter) ([]*Organization,
 }
 
 func (c *Client) GetOrganization(organizationID int64) (*Organization, error) {
+	return c.GetOrganizationContext(context.Background(), organizationID)
+}
+
+func (c *Client) GetOrganizationContext(ctx context.Context, organizationID int64) (*Organization, error) {
 	res := &Organization{}
 	path := fmt.Sprintf("api/v2/organization/%d", organizationID)
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
@@ -57,8 +66,12 @@ func (c *Client) GetOrganization(organizationID int64) (*Organization, error) {
 }
 
 func (c *Client) CreateOrganization(org *Organization) (*Organization, error) {
+	return c.CreateOrganizationContext(context.Background(), org)
+}
+
+func (c *Client) CreateOrganizationContext(ctx context.Context, org *Organization) (*Organization, error) {
 	organization := &Organization{}
-	req := c.newRequest(http.MethodPost, "api/v2/organization", requestBody(org))
+	req := c.newRequest(ctx, http.MethodPost, "api/v2/organization", requestBody(org))
 	if err := c.do(req, organization); err != nil {
 		return nil, err
 	}
@@ -66,9 +79,13 @@ func (c *Client) CreateOrganization(org *Organization) (*Organization, error) {
 }
 
 func (c *Client) UpdateOrganization(org *Organization) (*Organization, error) {
+	return c.UpdateOrganizationContext(context.Background(), org)
+}
+
+func (c *Client) UpdateOrganizationContext(ctx context.Context, org *Organization) (*Organization, error) {
 	organization := &Organization{}
 	path := fmt.Sprintf("api/v2/organization/%d", org.ID)
-	req := c.newRequest(http.MethodPut, path, requestBody(org))
+	req := c.newRequest(ctx, http.MethodPut, path, requestBody(org))
 	if err := c.do(req, organization); err != nil {
 		return nil, err
 	}
@@ -76,12 +93,16 @@ func (c *Client) UpdateOrganization(org *Organization) (*Organization, error) {
 }
 
 func (c *Client) DeleteOrganization(org *Organization, force bool) error {
+	return c.DeleteOrganizationContext(context.Background(), org, force)
+}
+
+func (c *Client) DeleteOrganizationContext(ctx context.Context, org *Organization, force bool) error {
 	qs := ""
 	if force {
-		qs = "&force=true"
+		qs = "?force=true"
 	}
 	path := fmt.Sprintf("api/v2/organization/%d%s", org.ID, qs)
-	req := c.newRequest(http.MethodDelete, path, nil)
+	req := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(req, nil); err != nil {
 		return err
 	}
@@ -89,8 +110,12 @@ func (c *Client) DeleteOrganization(org *Organization, force bool) error {
 }
 
 func (c *Client) ForcePasswordReset(organizationID int64) error {
+	return c.ForcePasswordResetContext(context.Background(), organizationID)
+}
+
+func (c *Client) ForcePasswordResetContext(ctx context.Context, organizationID int64) error {
 	path := fmt.Sprintf("api/v2/organization/%d/force_password_reset", organizationID)
-	req := c.newRequest(http.MethodPost, path, nil)
+	req := c.newRequest(ctx, http.MethodPost, path, nil)
 	if err := c.do(req, nil); err != nil {
 		return err
 	}
@@ -98,9 +123,13 @@ func (c *Client) ForcePasswordReset(organizationID int64) error {
 }
 
 func (c *Client) GetOrganizationMeta(organizationID int64, key string) (*MetaObject, error) {
+	return c.GetOrganizationMetaContext(context.Background(), organizationID, key)
+}
+
+func (c *Client) GetOrganizationMetaContext(ctx context.Context, organizationID int64, key string) (*MetaObject, error) {
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/organization/%d/meta/%s", organizationID, key)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, mo); err != nil {
 		return nil, err
 	}
@@ -108,12 +137,16 @@ func (c *Client) GetOrganizationMeta(organizationID int64, key string) (*MetaObj
 }
 
 func (c *Client) CreateOrganizationMeta(organizationID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
+	return c.CreateOrganizationMetaContext(context.Background(), organizationID, key, meta, silent)
+}
+
+func (c *Client) CreateOrganizationMetaContext(ctx context.Context, organizationID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/organization/%d/meta/%s?%s", organizationID, key, query.Encode())
-	request := c.newRequest(http.MethodPost, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -122,13 +155,17 @@ func (c *Client) CreateOrganizationMeta(organizationID int64, key string, meta M
 }
 
 func (c *Client) UpdateOrganizationMeta(organizationID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
+	return c.UpdateOrganizationMetaContext(context.Background(), organizationID, key, meta, silent, createMissing)
+}
+
+func (c *Client) UpdateOrganizationMetaContext(ctx context.Context, organizationID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent":         []string{fmt.Sprintf("%t", silent)},
 		"create_missing": []string{fmt.Sprintf("%t", createMissing)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/organization/%d/meta/%s?%s", organizationID, key, query.Encode())
-	request := c.newRequest(http.MethodPut, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -137,11 +174,15 @@ func (c *Client) UpdateOrganizationMeta(organizationID int64, key string, meta M
 }
 
 func (c *Client) DeleteOrganizationMeta(organizationID int64, key string, silent bool) error {
+	return c.DeleteOrganizationMetaContext(context.Background(), organizationID, key, silent)
+}
+
+func (c *Client) DeleteOrganizationMetaContext(ctx context.Context, organizationID int64, key string, silent bool) error {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	path := fmt.Sprintf("api/v2/organization/%d/meta/%s?%s", organizationID, key, query.Encode())
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
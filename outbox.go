@@ -0,0 +1,220 @@
+package lynx
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrOutboxFull is returned when a message is dropped because the outbox is full
+var ErrOutboxFull = errors.New("outbox full")
+
+// DropPolicy decides which message is dropped when the outbox is full
+type DropPolicy int
+
+const (
+	// DropNewest rejects new messages when the outbox is full
+	DropNewest DropPolicy = iota
+	// DropOldest removes the oldest queued message to make room for the new one. Every message
+	// pushed to a full outbox rewrites and syncs the whole file, so the limits should be set well
+	// above the expected backlog when messages are published at a high rate while disconnected.
+	DropOldest
+)
+
+// OutboxOptions configures an Outbox
+type OutboxOptions struct {
+	// Path is the file the queued messages are stored in, it is created if missing
+	Path string
+	// MaxMessages limits the number of queued messages, 0 means unlimited
+	MaxMessages int
+	// MaxBytes limits the total size of the queued payloads, 0 means unlimited
+	MaxBytes int64
+	// DropPolicy decides what happens when a limit is reached
+	DropPolicy DropPolicy
+}
+
+// outboxRecord is a queued message, stored as one JSON line in the outbox file
+type outboxRecord struct {
+	Topic   string          `json:"topic"`
+	QoS     byte            `json:"qos"`
+	Payload json.RawMessage `json:"payload"`
+	seq     uint64
+}
+
+// Outbox is a durable queue of MQTT messages published while the client is disconnected.
+// Messages are appended to a file and flushed in order when the client reconnects,
+// the file is rewritten when messages are removed. Set it in Options.Outbox to use it.
+type Outbox struct {
+	opts  OutboxOptions
+	mu    sync.Mutex
+	f     *os.File
+	queue []outboxRecord
+	size  int64
+	seq   uint64
+	// flushMu serializes flushes so that messages are published in order
+	flushMu sync.Mutex
+}
+
+// NewOutbox opens the outbox file, loading any messages left from a previous run.
+func NewOutbox(opts OutboxOptions) (*Outbox, error) {
+	o := &Outbox{opts: opts}
+	f, err := os.Open(opts.Path)
+	if err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<24)
+		for scanner.Scan() {
+			rec := outboxRecord{}
+			// A partially written last line after a crash is skipped
+			if json.Unmarshal(scanner.Bytes(), &rec) == nil {
+				o.seq++
+				rec.seq = o.seq
+				o.queue = append(o.queue, rec)
+				o.size += int64(len(rec.Payload))
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err := o.rewrite(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Len returns the number of queued messages.
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.queue)
+}
+
+// Close closes the outbox file, queued messages are kept for the next NewOutbox.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.f.Close()
+}
+
+// rewrite replaces the outbox file with the current queue. o.mu must be held, or o not yet shared.
+func (o *Outbox) rewrite() error {
+	tmp := o.opts.Path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, rec := range o.queue {
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, o.opts.Path); err != nil {
+		return err
+	}
+	if o.f != nil {
+		o.f.Close()
+	}
+	o.f, err = os.OpenFile(o.opts.Path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if dir, err := os.Open(filepath.Dir(o.opts.Path)); err == nil {
+		_ = dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+func (o *Outbox) full(extra int64) bool {
+	return (o.opts.MaxMessages > 0 && len(o.queue)+1 > o.opts.MaxMessages) ||
+		(o.opts.MaxBytes > 0 && o.size+extra > o.opts.MaxBytes)
+}
+
+// push appends a message to the outbox, applying the drop policy if it is full.
+func (o *Outbox) push(rec outboxRecord) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.seq++
+	rec.seq = o.seq
+	size := int64(len(rec.Payload))
+	if o.full(size) {
+		if o.opts.DropPolicy == DropNewest || (o.opts.MaxBytes > 0 && size > o.opts.MaxBytes) {
+			return ErrOutboxFull
+		}
+		for len(o.queue) > 0 && o.full(size) {
+			o.size -= int64(len(o.queue[0].Payload))
+			o.queue = o.queue[1:]
+		}
+		o.queue = append(o.queue, rec)
+		o.size += size
+		return o.rewrite()
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := o.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	o.queue = append(o.queue, rec)
+	o.size += size
+	return nil
+}
+
+// flush publishes queued messages in order until the outbox is empty or publish fails.
+// The file is rewritten once afterwards, so a crash during a flush can cause messages to be published again.
+func (o *Outbox) flush(publish func(outboxRecord) error) error {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+	var err error
+	removed := false
+	for {
+		o.mu.Lock()
+		if len(o.queue) == 0 {
+			o.mu.Unlock()
+			break
+		}
+		rec := o.queue[0]
+		o.mu.Unlock()
+		if err = publish(rec); err != nil {
+			break
+		}
+		o.mu.Lock()
+		// The head may have been dropped by DropOldest while publishing
+		if len(o.queue) > 0 && o.queue[0].seq == rec.seq {
+			o.size -= int64(len(rec.Payload))
+			o.queue = o.queue[1:]
+			removed = true
+		}
+		o.mu.Unlock()
+	}
+	if removed {
+		o.mu.Lock()
+		rewriteErr := o.rewrite()
+		o.mu.Unlock()
+		if err == nil {
+			err = rewriteErr
+		}
+	}
+	return err
+}
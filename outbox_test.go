@@ -0,0 +1,126 @@
+package lynx_test
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+func TestOutbox(t *testing.T) {
+	b := lynxtest.NewTestBroker(t)
+
+	path := filepath.Join(t.TempDir(), "outbox")
+	outbox, err := lynx.NewOutbox(lynx.OutboxOptions{Path: path, MaxMessages: 2, DropPolicy: lynx.DropOldest})
+	if err != nil {
+		t.Fatalf("NewOutbox() error = %v", err)
+	}
+	c := lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.AuthNone{},
+		MqttOptions:   b.MqttOptions("test", nil, nil),
+		Outbox:        outbox,
+	})
+	for i := range 3 {
+		if err := c.Publish("obj/value", lynx.Message{Value: float64(i)}, 1); err != nil {
+			t.Fatalf("Publish() while disconnected error = %v", err)
+		}
+	}
+	if outbox.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", outbox.Len())
+	}
+	if err := outbox.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Reopen to verify the queue survives a restart
+	outbox, err = lynx.NewOutbox(lynx.OutboxOptions{Path: path, MaxMessages: 2})
+	if err != nil {
+		t.Fatalf("NewOutbox() error = %v", err)
+	}
+	defer outbox.Close()
+	c = lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.AuthNone{},
+		MqttOptions:   b.MqttOptions("test", nil, nil),
+		Outbox:        outbox,
+	})
+	if err := c.Publish("obj/value", lynx.Message{Value: 3}, 1); !errors.Is(err, lynx.ErrOutboxFull) {
+		t.Fatalf("Publish() to full outbox error = %v, want ErrOutboxFull", err)
+	}
+	if err := c.MQTTConnect(); err != nil {
+		t.Fatalf("MQTTConnect() error = %v", err)
+	}
+	t.Cleanup(c.MQTTDisconnect)
+
+	deadline := time.Now().Add(time.Second)
+	for outbox.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+	var values []float64
+	for _, p := range b.Published() {
+		msg, _ := p.Message()
+		values = append(values, msg.Value)
+	}
+	if len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Errorf("published values = %v, want [1 2]", values)
+	}
+}
+
+func TestOutbox_PublishTimeout(t *testing.T) {
+	// A broker that accepts connections but never acknowledges published messages
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := packets.ReadPacket(conn); err != nil {
+			return
+		}
+		if err := packets.NewControlPacket(packets.Connack).Write(conn); err != nil {
+			return
+		}
+		for {
+			if _, err := packets.ReadPacket(conn); err != nil {
+				return
+			}
+		}
+	}()
+
+	outbox, err := lynx.NewOutbox(lynx.OutboxOptions{Path: filepath.Join(t.TempDir(), "outbox")})
+	if err != nil {
+		t.Fatalf("NewOutbox() error = %v", err)
+	}
+	defer outbox.Close()
+	b := lynxtest.NewTestBroker(t)
+	opts := b.MqttOptions("test", nil, nil)
+	opts.Servers = nil
+	opts.AddBroker("tcp://" + l.Addr().String())
+	c := lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.AuthNone{},
+		MqttOptions:   opts,
+		Outbox:        outbox,
+		MqttTimeout:   time.Millisecond * 100,
+	})
+	if err := c.MQTTConnect(); err != nil {
+		t.Fatalf("MQTTConnect() error = %v", err)
+	}
+	defer c.MQTTDisconnect()
+
+	// The message was handed to the MQTT client which may still deliver it, so it is not queued
+	if err := c.Publish("obj/value", lynx.Message{Value: 1}, 1); !errors.Is(err, lynx.ErrTimeout) {
+		t.Errorf("Publish() error = %v, want timeout", err)
+	}
+	if outbox.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", outbox.Len())
+	}
+}
@@ -0,0 +1,38 @@
+package lynx
+
+import (
+	"context"
+	"iter"
+)
+
+// pageFunc fetches the page starting at offset and returns its entries and the total number of entries.
+type pageFunc[T any] func(ctx context.Context, offset int64) ([]T, int64, error)
+
+// paginate returns an iterator that fetches pages from start until total entries have been
+// yielded or an empty page is returned. An error is yielded once and ends the iteration.
+func paginate[T any](ctx context.Context, start int64, fetch pageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		offset := start
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			page, total, err := fetch(ctx, offset)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, v := range page {
+				if !yield(v, nil) {
+					return
+				}
+			}
+			offset += int64(len(page))
+			if len(page) == 0 || offset >= total {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package lynx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit configures client side throttling of API calls.
+// A 429 Too Many Requests response pauses all requests until the time given by
+// Retry-After (or one second) and halves the request rate, which then slowly
+// recovers to RequestsPerSecond as requests succeed.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained request rate, 0 disables the token bucket
+	RequestsPerSecond float64
+	// Burst is the number of requests that can be made at once, defaults to 1
+	Burst int
+	// MaxInFlight caps the number of concurrent requests, 0 means unlimited
+	MaxInFlight int
+}
+
+type rateLimiter struct {
+	mu          sync.Mutex
+	limit       float64
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	sem         chan struct{}
+}
+
+func newRateLimiter(conf *RateLimit) *rateLimiter {
+	if conf == nil {
+		return nil
+	}
+	l := &rateLimiter{
+		limit: conf.RequestsPerSecond,
+		rate:  conf.RequestsPerSecond,
+		burst: float64(max(conf.Burst, 1)),
+	}
+	l.tokens = l.burst
+	if conf.MaxInFlight > 0 {
+		l.sem = make(chan struct{}, conf.MaxInFlight)
+	}
+	return l
+}
+
+// wait blocks until a request may be sent. Every successful call must be followed by a call to done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	for {
+		d := l.reserve()
+		if d == 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, d); err != nil {
+			l.done()
+			return err
+		}
+	}
+}
+
+func (l *rateLimiter) done() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before trying again.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.limit <= 0 {
+		return 0
+	}
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// observe adapts the limiter to the response from the server.
+func (l *rateLimiter) observe(response *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if response.StatusCode == http.StatusTooManyRequests {
+		d, ok := retryAfter(response.Header.Get("Retry-After"))
+		if !ok {
+			d = time.Second
+		}
+		if until := time.Now().Add(d); until.After(l.pausedUntil) {
+			l.pausedUntil = until
+		}
+		if l.limit > 0 {
+			l.rate = max(l.rate/2, l.limit/16)
+			l.tokens = 0
+		}
+		return
+	}
+	if l.rate < l.limit && response.StatusCode < http.StatusInternalServerError {
+		l.rate = min(l.limit, l.rate+l.limit/20)
+	}
+}
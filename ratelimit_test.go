@@ -0,0 +1,135 @@
+package lynx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_Rate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	c := NewClient(&Options{
+		Authenticator: AuthNone{},
+		APIBase:       srv.URL,
+		RateLimit:     &RateLimit{RequestsPerSecond: 20, Burst: 2},
+	})
+	start := time.Now()
+	for range 6 {
+		if err := c.Ping(); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	}
+	// 2 requests from the burst, 4 more at 50ms intervals
+	if elapsed := time.Since(start); elapsed < time.Millisecond*180 {
+		t.Errorf("6 requests took %v, want at least 200ms", elapsed)
+	}
+}
+
+func TestRateLimit_MaxInFlight(t *testing.T) {
+	var inFlight, peak atomic.Int64
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+	}))
+	defer srv.Close()
+	c := NewClient(&Options{Authenticator: AuthNone{}, APIBase: srv.URL, RateLimit: &RateLimit{MaxInFlight: 2}})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = c.Ping()
+		}()
+	}
+	time.Sleep(time.Millisecond * 100)
+	close(release)
+	wg.Wait()
+	if p := peak.Load(); p != 2 {
+		t.Errorf("peak concurrent requests = %d, want 2", p)
+	}
+}
+
+func TestRateLimit_StreamedBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer srv.Close()
+	c := NewClient(&Options{Authenticator: AuthNone{}, APIBase: srv.URL, RateLimit: &RateLimit{MaxInFlight: 1}})
+
+	response, err := c.send(c.newRequest(context.Background(), http.MethodGet, "file", nil))
+	if err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	// The slot is held until the body of the first response is closed
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	if err := c.PingContext(ctx); err == nil {
+		t.Errorf("PingContext() with open body error = nil, want timeout")
+	}
+	response.Body.Close()
+	response.Body.Close()
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping() after close error = %v", err)
+	}
+}
+
+func TestRateLimit_FailedDownload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/file/download/x" {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(&Options{Authenticator: AuthNone{}, APIBase: srv.URL, RateLimit: &RateLimit{MaxInFlight: 1}})
+
+	if _, err := c.DownloadFile("x"); err == nil {
+		t.Fatal("DownloadFile() error = nil, want not found")
+	}
+	// The slot of the failed download is released
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.PingContext(ctx); err != nil {
+		t.Errorf("PingContext() after failed download error = %v", err)
+	}
+}
+
+func TestRateLimit_TooManyRequests(t *testing.T) {
+	l := newRateLimiter(&RateLimit{RequestsPerSecond: 16})
+	l.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"1"}}})
+	if d := l.reserve(); d < time.Millisecond*900 {
+		t.Errorf("reserve() after 429 = %v, want pause of about 1s", d)
+	}
+	if l.rate != 8 {
+		t.Errorf("rate after 429 = %v, want 8", l.rate)
+	}
+	for range 4 {
+		l.observe(&http.Response{StatusCode: http.StatusTooManyRequests})
+	}
+	if l.rate != 1 {
+		t.Errorf("rate after 5 429 = %v, want floor 1", l.rate)
+	}
+	l.observe(&http.Response{StatusCode: http.StatusInternalServerError})
+	if l.rate != 1 {
+		t.Errorf("rate after 500 = %v, want 1", l.rate)
+	}
+	for range 20 {
+		l.observe(&http.Response{StatusCode: http.StatusOK})
+	}
+	if l.rate != 16 {
+		t.Errorf("rate after recovery = %v, want 16", l.rate)
+	}
+}
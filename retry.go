@@ -0,0 +1,111 @@
+package lynx
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the client retries failed requests.
+// By default only idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) are retried,
+// POST requests are only replayed if RetryNonIdempotent is set.
+// Request bodies, including multipart file uploads, are rebuilt for every attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// Values below 2 disables retries.
+	MaxAttempts int
+	// MinBackoff is the base delay used for the exponential backoff.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, including delays requested by Retry-After.
+	MaxBackoff time.Duration
+	// RetryableStatus is the list of HTTP status codes that should be retried.
+	RetryableStatus []int
+	// RetryNonIdempotent allows retrying POST and PATCH requests as well.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a retry policy with 4 attempts, exponential backoff
+// between 200ms and 10s, retrying on 429, 502, 503 and 504.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  time.Millisecond * 200,
+		MaxBackoff:  time.Second * 10,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p *RetryPolicy) canRetry(r *http.Request) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		if !p.RetryNonIdempotent {
+			return false
+		}
+	}
+	return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+}
+
+func (p *RetryPolicy) retryStatus(code int) bool {
+	return slices.Contains(p.RetryableStatus, code)
+}
+
+// backoff returns the delay before the given retry attempt (starting at 1).
+// A Retry-After header on the previous response takes precedence over the computed delay.
+func (p *RetryPolicy) backoff(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if d, ok := retryAfter(response.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				return p.MaxBackoff
+			}
+			return d
+		}
+	}
+	d := p.MinBackoff << (attempt - 1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Equal jitter, spread the delay over [d/2, d]
+	return d/2 + rand.N(d/2+1)
+}
+
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
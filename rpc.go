@@ -0,0 +1,109 @@
+package lynx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// correlationSeparator separates the correlation id from the original Msg in request and reply messages
+const correlationSeparator = ":"
+
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// splitCorrelation splits the Msg of a message into correlation id and the original Msg.
+func splitCorrelation(msg string) (id, rest string) {
+	id, rest, _ = strings.Cut(msg, correlationSeparator)
+	return id, rest
+}
+
+// replyWaiters is the subscription on a reply topic and the requests waiting for a reply per correlation id
+type replyWaiters struct {
+	sub     uint64
+	pending map[string]chan Message
+}
+
+// handleReply routes a message received on a reply topic to the waiting request.
+func (c *Client) handleReply(replyTopic string) func(MQTTMessage) {
+	return func(m MQTTMessage) {
+		id, rest := splitCorrelation(m.Msg.Msg)
+		c.rpcMu.Lock()
+		ch, ok := c.replies[replyTopic].pending[id]
+		delete(c.replies[replyTopic].pending, id)
+		c.rpcMu.Unlock()
+		if ok {
+			m.Msg.Msg = rest
+			ch <- m.Msg
+		}
+	}
+}
+
+// Request publishes payload to topic and waits for the reply on replyTopic, or until ctx is done.
+//
+// The request and reply are correlated by an id put first in the Msg field, separated from the
+// original Msg by a colon, for example "4f1c2a9e0b3d7e11:on". The responder must reply with a Msg
+// starting with the same id. The id is removed from the Msg of the returned reply.
+// The client stays subscribed to replyTopic after the first request, alongside any other
+// subscription made through the client on the same topic. If the subscription is removed,
+// for example with Unsubscribe, the next request subscribes again.
+func (c *Client) Request(ctx context.Context, topic string, payload Message, replyTopic string) (Message, error) {
+	if err := c.subscribeReplies(replyTopic); err != nil {
+		return Message{}, err
+	}
+	id := newCorrelationID()
+	ch := make(chan Message, 1)
+	c.rpcMu.Lock()
+	c.replies[replyTopic].pending[id] = ch
+	c.rpcMu.Unlock()
+	defer func() {
+		c.rpcMu.Lock()
+		delete(c.replies[replyTopic].pending, id)
+		c.rpcMu.Unlock()
+	}()
+
+	if payload.Msg != "" {
+		payload.Msg = id + correlationSeparator + payload.Msg
+	} else {
+		payload.Msg = id
+	}
+	if err := c.PublishContext(ctx, topic, payload, 1); err != nil {
+		return Message{}, err
+	}
+	select {
+	case reply := <-ch:
+		return reply, nil
+	case <-ctx.Done():
+		return Message{}, fmt.Errorf("no reply on topic %s: %w", replyTopic, ctx.Err())
+	}
+}
+
+// subscribeReplies subscribes to replyTopic unless the subscription of an earlier request is still active.
+func (c *Client) subscribeReplies(replyTopic string) error {
+	c.rpcSubMu.Lock()
+	defer c.rpcSubMu.Unlock()
+	c.rpcMu.Lock()
+	reply, ok := c.replies[replyTopic]
+	if !ok {
+		reply = &replyWaiters{pending: make(map[string]chan Message)}
+		c.replies[replyTopic] = reply
+	}
+	sub := reply.sub
+	c.rpcMu.Unlock()
+	if sub != 0 && c.subscribed(replyTopic, sub) {
+		return nil
+	}
+	sub, err := c.subscribe(replyTopic, 1, c.messageHandler(c.handleReply(replyTopic)))
+	if err != nil {
+		return err
+	}
+	c.rpcMu.Lock()
+	reply.sub = sub
+	c.rpcMu.Unlock()
+	return nil
+}
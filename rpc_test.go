@@ -0,0 +1,65 @@
+package lynx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+)
+
+func TestClient_Request(t *testing.T) {
+	b := lynxtest.NewTestBroker(t)
+
+	c := b.NewTestClient(t, "requester", nil)
+	responder := b.NewTestClient(t, "responder", nil)
+
+	if err := responder.Subscribe("cmd/double", 0, func(topic string, msg lynx.Message) {
+		id, text, _ := strings.Cut(msg.Msg, ":")
+		go responder.Publish("cmd/double/reply", lynx.Message{Value: msg.Value * 2, Msg: id + ":" + text + "!"}, 0)
+	}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	reply, err := c.Request(ctx, "cmd/double", lynx.Message{Value: 21, Msg: "hi"}, "cmd/double/reply")
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if reply.Value != 42 || reply.Msg != "hi!" {
+		t.Errorf("Request() = %+v", reply)
+	}
+
+	// Other subscriptions on the reply topic do not affect requests, and a removed
+	// reply subscription is restored by the next request
+	sub, err := c.SubscribeChan("cmd/double/reply", 0, 4)
+	if err != nil {
+		t.Fatalf("SubscribeChan() error = %v", err)
+	}
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	for _, unsubscribe := range []bool{false, true} {
+		if unsubscribe {
+			if err := c.Unsubscribe("cmd/double/reply"); err != nil {
+				t.Fatalf("Unsubscribe() error = %v", err)
+			}
+		}
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		reply, err := c.Request(ctx, "cmd/double", lynx.Message{Value: 2}, "cmd/double/reply")
+		cancel()
+		if err != nil || reply.Value != 4 {
+			t.Errorf("Request() after Unsubscribe(%v) = %+v, %v", unsubscribe, reply, err)
+		}
+	}
+
+	ctx, cancel = context.WithTimeout(t.Context(), time.Millisecond*50)
+	defer cancel()
+	if _, err := c.Request(ctx, "cmd/none", lynx.Message{}, "cmd/none/reply"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Request() without responder error = %v", err)
+	}
+}
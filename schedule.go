@@ -1,6 +1,7 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -23,13 +24,17 @@ type Schedule struct {
 }
 
 func (c *Client) GetSchedules(installationID int64, executor string) ([]*Schedule, error) {
+	return c.GetSchedulesContext(context.Background(), installationID, executor)
+}
+
+func (c *Client) GetSchedulesContext(ctx context.Context, installationID int64, executor string) ([]*Schedule, error) {
 	res := make([]*Schedule, 0, 20)
 	query := url.Values{}
 	if executor != "" {
 		query["executor"] = []string{executor}
 	}
 	path := fmt.Sprintf("api/v2/schedule/%d?%s", installationID, query.Encode())
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, &res); err != nil {
 		return nil, err
 	}
@@ -37,9 +42,13 @@ func (c *Client) GetSchedules(installationID int64, executor string) ([]*Schedul
 }
 
 func (c *Client) GetSchedule(installationID, scheduleID int64) (*Schedule, error) {
+	return c.GetScheduleContext(context.Background(), installationID, scheduleID)
+}
+
+func (c *Client) GetScheduleContext(ctx context.Context, installationID, scheduleID int64) (*Schedule, error) {
 	schedule := &Schedule{}
 	path := fmt.Sprintf("api/v2/schedule/%d/%d", installationID, scheduleID)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, schedule); err != nil {
 		return nil, err
 	}
@@ -47,9 +56,13 @@ func (c *Client) GetSchedule(installationID, scheduleID int64) (*Schedule, error
 }
 
 func (c *Client) CreateSchedule(s *Schedule) (*Schedule, error) {
+	return c.CreateScheduleContext(context.Background(), s)
+}
+
+func (c *Client) CreateScheduleContext(ctx context.Context, s *Schedule) (*Schedule, error) {
 	schedule := &Schedule{}
 	path := fmt.Sprintf("api/v2/schedule/%d", s.InstallationID)
-	request := c.newRequest(http.MethodPost, path, requestBody(s))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(s))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, schedule); err != nil {
 		return nil, err
@@ -58,9 +71,13 @@ func (c *Client) CreateSchedule(s *Schedule) (*Schedule, error) {
 }
 
 func (c *Client) UpdateSchedule(s *Schedule) (*Schedule, error) {
+	return c.UpdateScheduleContext(context.Background(), s)
+}
+
+func (c *Client) UpdateScheduleContext(ctx context.Context, s *Schedule) (*Schedule, error) {
 	schedule := &Schedule{}
 	path := fmt.Sprintf("api/v2/schedule/%d/%d", s.InstallationID, s.ID)
-	request := c.newRequest(http.MethodPut, path, requestBody(s))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(s))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, schedule); err != nil {
 		return nil, err
@@ -69,8 +86,12 @@ func (c *Client) UpdateSchedule(s *Schedule) (*Schedule, error) {
 }
 
 func (c *Client) DeleteSchedule(s *Schedule) error {
+	return c.DeleteScheduleContext(context.Background(), s)
+}
+
+func (c *Client) DeleteScheduleContext(ctx context.Context, s *Schedule) error {
 	path := fmt.Sprintf("api/v2/schedule/%d/%d", s.InstallationID, s.ID)
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}
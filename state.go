@@ -0,0 +1,153 @@
+package lynx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StateStore keeps the current value of every topic in an installation. It is seeded from Status
+// and then kept up to date from MQTT. It is safe for concurrent use.
+type StateStore struct {
+	c              *Client
+	installationID int64
+	clientID       int64
+	topics         []string
+	subs           []uint64
+
+	mu        sync.RWMutex
+	state     map[string]LogEntry
+	handlers  map[int]func(LogEntry)
+	handlerID int
+}
+
+// NewStateStore creates a StateStore for the installation, following the topics in topicFilter or
+// all topics if it is empty. Topics are given without the client id prefix, for example obj/zwave/1/temperature.
+// The MQTT client must be connected. Close must be called to remove the MQTT subscriptions.
+//
+// The subscriptions are made on the client id prefixed topics, alongside any other subscription
+// made through the client on the same topics.
+func (c *Client) NewStateStore(ctx context.Context, installationID int64, topicFilter []string) (*StateStore, error) {
+	clientID, err := c.installationClientID(ctx, installationID)
+	if err != nil {
+		return nil, err
+	}
+	s := &StateStore{
+		c:              c,
+		installationID: installationID,
+		clientID:       clientID,
+		state:          make(map[string]LogEntry),
+		handlers:       make(map[int]func(LogEntry)),
+	}
+	if len(topicFilter) == 0 {
+		s.topics = []string{fmt.Sprintf("%d/#", clientID)}
+	} else {
+		for _, topic := range topicFilter {
+			s.topics = append(s.topics, fmt.Sprintf("%d/%s", clientID, topic))
+		}
+	}
+	// Subscribe before fetching the status so no update is missed in between,
+	// older values from the status never overwrite newer ones from MQTT.
+	for _, topic := range s.topics {
+		id, err := c.subscribe(topic, 0, c.messageHandler(s.received))
+		if err != nil {
+			_ = s.Close()
+			return nil, err
+		}
+		s.subs = append(s.subs, id)
+	}
+	status, err := c.StatusContext(ctx, installationID, topicFilter)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	for _, entry := range status {
+		s.update(*entry)
+	}
+	return s, nil
+}
+
+func (s *StateStore) received(m MQTTMessage) {
+	_, topic, err := ParseTopic(m.Topic)
+	if err != nil {
+		return
+	}
+	// Messages without a timestamp are current values
+	timestamp := m.Msg.Timestamp
+	if timestamp == 0 {
+		timestamp = float64(time.Now().UnixMicro()) / 1e6
+	}
+	s.update(LogEntry{
+		ClientID:       s.clientID,
+		InstallationID: s.installationID,
+		Message:        m.Msg.Msg,
+		Timestamp:      timestamp,
+		Topic:          topic,
+		Value:          m.Msg.Value,
+	})
+}
+
+func (s *StateStore) update(entry LogEntry) {
+	s.mu.Lock()
+	if old, ok := s.state[entry.Topic]; ok && old.Timestamp > entry.Timestamp {
+		s.mu.Unlock()
+		return
+	}
+	s.state[entry.Topic] = entry
+	handlers := make([]func(LogEntry), 0, len(s.handlers))
+	for _, h := range s.handlers {
+		handlers = append(handlers, h)
+	}
+	s.mu.Unlock()
+	for _, h := range handlers {
+		h(entry)
+	}
+}
+
+// Get returns the current value of topic.
+func (s *StateStore) Get(topic string) (LogEntry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.state[topic]
+	return entry, ok
+}
+
+// Snapshot returns a copy of the current state of all topics.
+func (s *StateStore) Snapshot() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make(Status, 0, len(s.state))
+	for _, entry := range s.state {
+		res = append(res, &entry)
+	}
+	return res
+}
+
+// OnChange registers handler to be called with every new value, it returns a function that removes the handler.
+// Handlers are called from the MQTT client and must not block.
+func (s *StateStore) OnChange(handler func(entry LogEntry)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlerID++
+	id := s.handlerID
+	s.handlers[id] = handler
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.handlers, id)
+	}
+}
+
+// Close removes the MQTT subscriptions of the store.
+func (s *StateStore) Close() error {
+	var errs []error
+	for i, id := range s.subs {
+		if err := s.c.unsubscribe(s.topics[i], id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.subs = nil
+	return errors.Join(errs...)
+}
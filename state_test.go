@@ -0,0 +1,77 @@
+package lynx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+)
+
+func TestClient_NewStateStore(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	b := lynxtest.NewTestBroker(t)
+
+	installation := srv.AddInstallation(&lynx.InstallationRow{ClientID: 55})
+	srv.AddLog(installation.ID,
+		lynx.LogEntry{Topic: "obj/a", Value: 1, Timestamp: 100},
+		lynx.LogEntry{Topic: "obj/b", Value: 2, Timestamp: 100},
+	)
+	c := b.NewTestClient(t, "test", &lynx.Options{APIBase: srv.URL})
+
+	store, err := c.NewStateStore(t.Context(), installation.ID, nil)
+	if err != nil {
+		t.Fatalf("NewStateStore() error = %v", err)
+	}
+	defer store.Close()
+	if entry, ok := store.Get("obj/a"); !ok || entry.Value != 1 {
+		t.Errorf("Get() = %+v, %v", entry, ok)
+	}
+
+	changes := make(chan lynx.LogEntry, 1)
+	remove := store.OnChange(func(entry lynx.LogEntry) {
+		changes <- entry
+	})
+	defer remove()
+	b.InjectMessage("55/obj/a", lynx.Message{Value: 10, Timestamp: 200})
+	select {
+	case entry := <-changes:
+		if entry.Topic != "obj/a" || entry.Value != 10 || entry.InstallationID != installation.ID {
+			t.Errorf("OnChange() entry = %+v", entry)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("OnChange() not called")
+	}
+	if m := store.Snapshot().Map(); len(m) != 2 || m["obj/a"].Value != 10 {
+		t.Errorf("Snapshot() = %v", m)
+	}
+
+	// A message without timestamp is the current value
+	b.InjectMessage("55/obj/b", lynx.Message{Value: 20})
+	select {
+	case entry := <-changes:
+		if entry.Topic != "obj/b" || entry.Value != 20 || entry.Timestamp < 200 {
+			t.Errorf("OnChange() entry without timestamp = %+v", entry)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("OnChange() not called for message without timestamp")
+	}
+}
+
+func TestClient_NewStateStoreCancelled(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	installation := srv.AddInstallation(&lynx.InstallationRow{ClientID: 55})
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, APIBase: srv.URL})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := c.NewStateStore(ctx, installation.ID, nil); err == nil {
+		t.Fatal("NewStateStore() with cancelled ctx error = nil")
+	}
+	if r := srv.Requests(); len(r) != 0 {
+		t.Errorf("requests = %+v, want none", r)
+	}
+}
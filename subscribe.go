@@ -0,0 +1,253 @@
+package lynx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MessageHandler is called with every decoded message received on a subscription
+type MessageHandler func(topic string, msg Message)
+
+// DecodeErrorHandler is called when the payload of a received message is not a valid Lynx message
+type DecodeErrorHandler func(topic string, payload []byte, err error)
+
+// Subscription is a channel based subscription created by SubscribeChan
+type Subscription struct {
+	// C receives the messages of the subscription. It is closed by Unsubscribe.
+	C <-chan MQTTMessage
+
+	c      *Client
+	topic  string
+	id     uint64
+	ch     chan MQTTMessage
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *Client) decodeError(topic string, payload []byte, err error) {
+	if c.opt.DecodeErrorHandler != nil {
+		c.opt.DecodeErrorHandler(topic, payload, err)
+		return
+	}
+	log.Printf("MQTT: invalid message on %s: %s", topic, err.Error())
+}
+
+func (c *Client) messageHandler(handler func(MQTTMessage)) mqtt.MessageHandler {
+	return func(_ mqtt.Client, m mqtt.Message) {
+		msg := Message{}
+		if err := json.Unmarshal(m.Payload(), &msg); err != nil {
+			c.decodeError(m.Topic(), m.Payload(), err)
+			return
+		}
+		handler(MQTTMessage{Topic: m.Topic(), QoS: m.Qos(), Msg: msg})
+	}
+}
+
+// filterSubscriptions are the subscriptions on a topic filter. The broker subscription is shared
+// with the highest QoS of the subscriptions, and received messages are passed to every handler.
+// It is kept to restore the subscription after reconnects.
+type filterSubscriptions struct {
+	qos      byte
+	handlers map[uint64]mqtt.MessageHandler
+}
+
+func (c *Client) mqttSubscribe(topic string, qos byte, handler mqtt.MessageHandler) error {
+	ctx, cancel := c.mqttContext()
+	defer cancel()
+	token := c.Mqtt.Subscribe(topic, qos, handler)
+	if err := waitToken(ctx, token, "subscribe", topic); err != nil {
+		var timeout *TimeoutError
+		if errors.As(err, &timeout) {
+			return err
+		}
+		return fmt.Errorf("error subscribing to topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// dispatch returns the handler of the broker subscription on topic, passing messages to all subscriptions on it.
+func (c *Client) dispatch(topic string) mqtt.MessageHandler {
+	return func(mq mqtt.Client, m mqtt.Message) {
+		c.subMu.Lock()
+		var handlers []mqtt.MessageHandler
+		if subs, ok := c.subs[topic]; ok {
+			for _, id := range slices.Sorted(maps.Keys(subs.handlers)) {
+				handlers = append(handlers, subs.handlers[id])
+			}
+		}
+		c.subMu.Unlock()
+		for _, h := range handlers {
+			h(mq, m)
+		}
+	}
+}
+
+// subscribe adds a subscription on topic and returns its id, used to remove it with unsubscribe.
+// The broker is only subscribed to for the first subscription on topic or when qos is higher than before.
+// Subscriptions are restored on reconnect.
+func (c *Client) subscribe(topic string, qos byte, handler mqtt.MessageHandler) (uint64, error) {
+	c.subMu.Lock()
+	c.subID++
+	id := c.subID
+	subs, ok := c.subs[topic]
+	if !ok {
+		subs = &filterSubscriptions{qos: qos, handlers: make(map[uint64]mqtt.MessageHandler)}
+		c.subs[topic] = subs
+	}
+	subs.handlers[id] = handler
+	update := !ok || qos > subs.qos
+	subs.qos = max(subs.qos, qos)
+	c.subMu.Unlock()
+	if !update {
+		return id, nil
+	}
+	if err := c.mqttSubscribe(topic, qos, c.dispatch(topic)); err != nil {
+		c.subMu.Lock()
+		delete(subs.handlers, id)
+		if len(subs.handlers) == 0 && c.subs[topic] == subs {
+			delete(c.subs, topic)
+		}
+		c.subMu.Unlock()
+		return 0, err
+	}
+	return id, nil
+}
+
+// unsubscribe removes the subscription with id from topic, and the broker subscription if it was the last one.
+func (c *Client) unsubscribe(topic string, id uint64) error {
+	c.subMu.Lock()
+	subs, ok := c.subs[topic]
+	if !ok {
+		c.subMu.Unlock()
+		return nil
+	}
+	delete(subs.handlers, id)
+	last := len(subs.handlers) == 0
+	if last {
+		delete(c.subs, topic)
+	}
+	c.subMu.Unlock()
+	if !last {
+		return nil
+	}
+	ctx, cancel := c.mqttContext()
+	defer cancel()
+	return waitToken(ctx, c.Mqtt.Unsubscribe(topic), "unsubscribe", topic)
+}
+
+// subscribed reports whether the subscription with id on topic is active.
+func (c *Client) subscribed(topic string, id uint64) bool {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	subs, ok := c.subs[topic]
+	if !ok {
+		return false
+	}
+	_, ok = subs.handlers[id]
+	return ok
+}
+
+// resubscribe restores all registered subscriptions, it is called by the on connect handler.
+func (c *Client) resubscribe() {
+	c.subMu.Lock()
+	qos := make(map[string]byte, len(c.subs))
+	for topic, subs := range c.subs {
+		qos[topic] = subs.qos
+	}
+	c.subMu.Unlock()
+	for topic, q := range qos {
+		if err := c.mqttSubscribe(topic, q, c.dispatch(topic)); err != nil {
+			if c.opt.ResubscribeErrorHandler != nil {
+				c.opt.ResubscribeErrorHandler(topic, err)
+			} else {
+				log.Println("MQTT: resubscribe failed:", err.Error())
+			}
+		}
+	}
+}
+
+// Subscriptions returns the topics of all active subscriptions made through the client,
+// which are restored automatically when the client reconnects.
+func (c *Client) Subscriptions() []string {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	return slices.Sorted(maps.Keys(c.subs))
+}
+
+// Subscribe subscribes to topic, which may contain wildcards, and calls handler with every received message
+// decoded as a Lynx message. Messages that can not be decoded are passed to Options.DecodeErrorHandler.
+// Other subscriptions on the same topic are kept, and every handler receives the messages.
+func (c *Client) Subscribe(topic string, qos byte, handler MessageHandler) error {
+	_, err := c.subscribe(topic, qos, c.messageHandler(func(m MQTTMessage) {
+		handler(m.Topic, m.Msg)
+	}))
+	return err
+}
+
+// SubscribeChan subscribes to topic, which may contain wildcards, and delivers every received message decoded
+// as a Lynx message on the C channel of the returned Subscription. The channel has the given buffer size
+// and must be read promptly, since a full channel blocks the delivery of all other messages on the client.
+func (c *Client) SubscribeChan(topic string, qos byte, buffer int) (*Subscription, error) {
+	ch := make(chan MQTTMessage, buffer)
+	s := &Subscription{
+		C:     ch,
+		c:     c,
+		topic: topic,
+		ch:    ch,
+		done:  make(chan struct{}),
+	}
+	id, err := c.subscribe(topic, qos, c.messageHandler(func(m MQTTMessage) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.closed {
+			return
+		}
+		select {
+		case s.ch <- m:
+		case <-s.done:
+		}
+	}))
+	if err != nil {
+		return nil, err
+	}
+	s.id = id
+	return s, nil
+}
+
+// Unsubscribe removes the subscription and closes C. The broker subscription is removed
+// when there are no other subscriptions on the topic.
+func (s *Subscription) Unsubscribe() error {
+	var err error
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+		err = s.c.unsubscribe(s.topic, s.id)
+	})
+	return err
+}
+
+// Unsubscribe removes all subscriptions on the given topics, including those made by
+// SubscribeChan, NewStateStore and Request.
+func (c *Client) Unsubscribe(topics ...string) error {
+	c.subMu.Lock()
+	for _, topic := range topics {
+		delete(c.subs, topic)
+	}
+	c.subMu.Unlock()
+	ctx, cancel := c.mqttContext()
+	defer cancel()
+	return waitToken(ctx, c.Mqtt.Unsubscribe(topics...), "unsubscribe", strings.Join(topics, ", "))
+}
@@ -0,0 +1,150 @@
+package lynx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+func TestClient_Subscribe(t *testing.T) {
+	b := lynxtest.NewTestBroker(t)
+
+	decodeErrors := make(chan string, 1)
+	c := b.NewTestClient(t, "test", &lynx.Options{
+		DecodeErrorHandler: func(topic string, payload []byte, err error) {
+			decodeErrors <- topic
+		},
+	})
+
+	sub, err := c.SubscribeChan("obj/#", 0, 1)
+	if err != nil {
+		t.Fatalf("SubscribeChan() error = %v", err)
+	}
+	b.Inject("obj/bad", 0, false, []byte("not json"))
+	b.InjectMessage("obj/dev/temp", lynx.Message{Value: 21.5, Timestamp: 1700000000})
+	select {
+	case m := <-sub.C:
+		if m.Topic != "obj/dev/temp" || m.Msg.Value != 21.5 {
+			t.Errorf("SubscribeChan() received %+v", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("SubscribeChan() no message received")
+	}
+	select {
+	case topic := <-decodeErrors:
+		if topic != "obj/bad" {
+			t.Errorf("DecodeErrorHandler topic = %s", topic)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("DecodeErrorHandler not called")
+	}
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	if _, ok := <-sub.C; ok {
+		t.Errorf("Subscription channel not closed")
+	}
+}
+
+func TestClient_Resubscribe(t *testing.T) {
+	b := lynxtest.NewTestBroker(t)
+
+	reconnected := make(chan struct{}, 2)
+	opts := b.MqttOptions("test", func(mqtt.Client) {
+		reconnected <- struct{}{}
+	}, nil)
+	opts.SetMaxReconnectInterval(time.Millisecond * 100)
+	c := b.NewTestClient(t, "test", &lynx.Options{MqttOptions: opts})
+	<-reconnected
+
+	received := make(chan lynx.Message, 1)
+	if err := c.Subscribe("obj/+/temp", 0, func(topic string, msg lynx.Message) {
+		received <- msg
+	}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	b.DropConnections()
+	select {
+	case <-reconnected:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("client did not reconnect")
+	}
+	b.InjectMessage("obj/dev/temp", lynx.Message{Value: 3})
+	select {
+	case msg := <-received:
+		if msg.Value != 3 {
+			t.Errorf("Subscribe() received %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("subscription not restored after reconnect")
+	}
+	if got := c.Subscriptions(); len(got) != 1 || got[0] != "obj/+/temp" {
+		t.Errorf("Subscriptions() = %v", got)
+	}
+}
+
+func TestClient_SubscribeSameTopic(t *testing.T) {
+	b := lynxtest.NewTestBroker(t)
+	c := b.NewTestClient(t, "test", nil)
+
+	received := make(chan lynx.Message, 2)
+	if err := c.Subscribe("obj/#", 0, func(topic string, msg lynx.Message) {
+		received <- msg
+	}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	first, err := c.SubscribeChan("obj/#", 0, 2)
+	if err != nil {
+		t.Fatalf("SubscribeChan() error = %v", err)
+	}
+	second, err := c.SubscribeChan("obj/#", 1, 2)
+	if err != nil {
+		t.Fatalf("SubscribeChan() error = %v", err)
+	}
+	next := func(ch <-chan lynx.MQTTMessage) (float64, bool) {
+		select {
+		case m := <-ch:
+			return m.Msg.Value, true
+		case <-time.After(time.Second):
+			return 0, false
+		}
+	}
+
+	b.InjectMessage("obj/a", lynx.Message{Value: 1})
+	if v, ok := next(first.C); !ok || v != 1 {
+		t.Errorf("first subscription received %v, %v", v, ok)
+	}
+	if v, ok := next(second.C); !ok || v != 1 {
+		t.Errorf("second subscription received %v, %v", v, ok)
+	}
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Errorf("Subscribe() handler not called")
+	}
+
+	// Removing one subscription keeps the others on the same topic
+	if err := first.Unsubscribe(); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	b.InjectMessage("obj/a", lynx.Message{Value: 2})
+	if v, ok := next(second.C); !ok || v != 2 {
+		t.Errorf("second subscription after first unsubscribed received %v, %v", v, ok)
+	}
+	if got := c.Subscriptions(); len(got) != 1 || got[0] != "obj/#" {
+		t.Errorf("Subscriptions() = %v", got)
+	}
+
+	if err := c.Unsubscribe("obj/#"); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+	if got := c.Subscriptions(); len(got) != 0 {
+		t.Errorf("Subscriptions() after Unsubscribe = %v", got)
+	}
+	if err := second.Unsubscribe(); err != nil {
+		t.Errorf("Unsubscribe() of removed subscription error = %v", err)
+	}
+}
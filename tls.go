@@ -0,0 +1,86 @@
+package lynx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// TLSOptions configures TLS for the connections to the API, and optionally to the MQTT broker,
+// for deployments with a private certificate authority or requiring client certificates.
+type TLSOptions struct {
+	// CAFile is a PEM bundle of the certificate authorities to trust instead of the system roots
+	CAFile string
+	// CertFile and KeyFile are the PEM encoded client certificate and key used for mutual TLS
+	CertFile string
+	KeyFile  string
+	// MinVersion is the minimum TLS version, for example tls.VersionTLS13, defaults to TLS 1.2
+	MinVersion uint16
+	// ServerName overrides the name used to verify the server certificate
+	ServerName         string
+	InsecureSkipVerify bool
+	// MQTT applies the options to the MQTT client as well, unless its TLS config is already set
+	MQTT bool
+}
+
+// Config loads the files and returns the TLS configuration.
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         o.MinVersion,
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+	if o.CAFile != "" {
+		data, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", o.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, fmt.Errorf("both certificate and key file must be set for client certificates")
+		}
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsOptionsFromConfig reads the tls section of conf, with the keys ca_file, cert_file,
+// key_file, min_version, server_name, insecure_skip_verify and mqtt.
+func tlsOptionsFromConfig(conf *viper.Viper) (*TLSOptions, error) {
+	o := &TLSOptions{
+		CAFile:             conf.GetString("tls.ca_file"),
+		CertFile:           conf.GetString("tls.cert_file"),
+		KeyFile:            conf.GetString("tls.key_file"),
+		ServerName:         conf.GetString("tls.server_name"),
+		InsecureSkipVerify: conf.GetBool("tls.insecure_skip_verify"),
+		MQTT:               conf.GetBool("tls.mqtt"),
+	}
+	if v := conf.GetString("tls.min_version"); v != "" {
+		version, ok := tlsVersions[v]
+		if !ok {
+			return nil, fmt.Errorf("unknown min_version %s, expected 1.0, 1.1, 1.2 or 1.3", v)
+		}
+		o.MinVersion = version
+	}
+	return o, nil
+}
@@ -0,0 +1,109 @@
+package lynx_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// writeClientCert writes a self signed client certificate and key to dir.
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
+func TestClient_TLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCert(t, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	var protos []string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protos = append(protos, r.Proto)
+	}))
+	srv.EnableHTTP2 = true
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &lynx.TLSOptions{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, MinVersion: tls.VersionTLS12}
+	for _, base := range []string{srv.URL, strings.Replace(srv.URL, "https", "h2", 1)} {
+		c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, APIBase: base, TLS: options})
+		if err := c.Ping(); err != nil {
+			t.Errorf("Ping() to %s error = %v", base, err)
+		}
+	}
+	if len(protos) != 2 || protos[1] != "HTTP/2.0" {
+		t.Errorf("protocols = %v, want HTTP/1.1 and HTTP/2.0", protos)
+	}
+
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, APIBase: srv.URL, TLS: &lynx.TLSOptions{CAFile: caFile}})
+	if err := c.Ping(); err == nil {
+		t.Errorf("Ping() without client certificate error = nil")
+	}
+	c = lynx.NewClient(&lynx.Options{Authenticator: lynx.AuthNone{}, APIBase: srv.URL, TLS: &lynx.TLSOptions{CAFile: keyFile}})
+	if err := c.Ping(); err == nil || !strings.Contains(err.Error(), "invalid TLS options") {
+		t.Errorf("Ping() with invalid CA file error = %v", err)
+	}
+
+	mqttOptions := mqtt.NewClientOptions()
+	lynx.NewClient(&lynx.Options{
+		Authenticator: lynx.AuthNone{},
+		APIBase:       srv.URL,
+		MqttOptions:   mqttOptions,
+		TLS:           &lynx.TLSOptions{CAFile: caFile, ServerName: "broker", MQTT: true},
+	})
+	if mqttOptions.TLSConfig == nil || mqttOptions.TLSConfig.RootCAs == nil || mqttOptions.TLSConfig.ServerName != "broker" {
+		t.Errorf("MQTT TLSConfig = %+v", mqttOptions.TLSConfig)
+	}
+}
@@ -0,0 +1,123 @@
+package lynx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Token is a bearer token, a zero Expiry means that it does not expire
+type Token struct {
+	AccessToken string
+	Expiry      time.Time
+}
+
+// expired reports whether the token expires within leeway
+func (t *Token) expired(leeway time.Duration) bool {
+	return !t.Expiry.IsZero() && time.Now().Add(leeway).After(t.Expiry)
+}
+
+// TokenSource returns new bearer tokens
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc is a function used as a TokenSource
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// renewer is implemented by authenticators with credentials that expire. The client uses it
+// to report errors obtaining credentials and to replay requests once after 401 Unauthorized.
+type renewer interface {
+	// authorize sets fresh credentials on r
+	authorize(r *http.Request) error
+	// renew discards the credentials used for r after they were rejected
+	renew(r *http.Request)
+}
+
+// defaultTokenLeeway is how long before expiry a token is refreshed if RefreshingAuth.Leeway is not set
+const defaultTokenLeeway = time.Minute
+
+// mqttTokenTimeout is the timeout for fetching a token when the MQTT client connects
+const mqttTokenTimeout = time.Second * 30
+
+// RefreshingAuth is an Authentication using bearer tokens from a TokenSource.
+// Tokens are refreshed before they expire and when the API rejects them, in which case the
+// request is replayed once. The MQTT client fetches the current token on every (re)connect.
+type RefreshingAuth struct {
+	Source TokenSource
+	// Leeway is how long before expiry a token is refreshed, defaults to 1 minute
+	Leeway time.Duration
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewRefreshingAuth returns a RefreshingAuth for source
+func NewRefreshingAuth(source TokenSource) *RefreshingAuth {
+	return &RefreshingAuth{Source: source}
+}
+
+// Token returns the current token, refreshing it if it expires within the leeway.
+func (a *RefreshingAuth) Token(ctx context.Context) (*Token, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	leeway := a.Leeway
+	if leeway <= 0 {
+		leeway = defaultTokenLeeway
+	}
+	if a.token != nil && !a.token.expired(leeway) {
+		return a.token, nil
+	}
+	token, err := a.Source.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing token: %w", err)
+	}
+	a.token = token
+	return token, nil
+}
+
+func (a *RefreshingAuth) authorize(r *http.Request) error {
+	token, err := a.Token(r.Context())
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return nil
+}
+
+// renew discards the current token unless it was already replaced after r was sent.
+func (a *RefreshingAuth) renew(r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != nil && r.Header.Get("Authorization") == "Bearer "+a.token.AccessToken {
+		a.token = nil
+	}
+}
+
+// SetHTTPAuth sets the current token on r, errors refreshing it are returned when the request is sent by the Client.
+func (a *RefreshingAuth) SetHTTPAuth(r *http.Request) {
+	_ = a.authorize(r)
+}
+
+// SetMQTTAuth makes the MQTT client use the current token as password on every connect.
+func (a *RefreshingAuth) SetMQTTAuth(o *mqtt.ClientOptions) {
+	o.SetCredentialsProvider(func() (string, string) {
+		ctx, cancel := context.WithTimeout(context.Background(), mqttTokenTimeout)
+		defer cancel()
+		token, err := a.Token(ctx)
+		if err != nil {
+			log.Println("MQTT: no credentials:", err.Error())
+			return "bearer", ""
+		}
+		return "bearer", token.AccessToken
+	})
+}
@@ -0,0 +1,119 @@
+package lynx_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// tokenSource issues token-1, token-2 and so on
+type tokenSource struct {
+	n      atomic.Int64
+	expiry time.Duration
+}
+
+func (s *tokenSource) Token(ctx context.Context) (*lynx.Token, error) {
+	return &lynx.Token{
+		AccessToken: fmt.Sprintf("token-%d", s.n.Add(1)),
+		Expiry:      time.Now().Add(s.expiry),
+	}, nil
+}
+
+func TestRefreshingAuth(t *testing.T) {
+	var mu sync.Mutex
+	valid := "token-2"
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, r.Header.Get("Authorization"))
+		if valid != "" && r.Header.Get("Authorization") != "Bearer "+valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	source := &tokenSource{expiry: time.Hour}
+	c := lynx.NewClient(&lynx.Options{Authenticator: lynx.NewRefreshingAuth(source), APIBase: srv.URL})
+
+	// token-1 is rejected, the request is replayed once with token-2
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	want := []string{"Bearer token-1", "Bearer token-2", "Bearer token-2"}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("Authorization = %v, want %v", seen, want)
+	}
+
+	// The request is only replayed once
+	mu.Lock()
+	valid = "none"
+	seen = nil
+	mu.Unlock()
+	if err := c.Ping(); err == nil {
+		t.Fatalf("Ping() with rejected tokens error = nil")
+	}
+	if len(seen) != 2 {
+		t.Errorf("requests = %v, want 2", seen)
+	}
+
+	// Tokens expiring within the leeway are refreshed before use
+	source = &tokenSource{expiry: time.Second * 30}
+	c = lynx.NewClient(&lynx.Options{Authenticator: lynx.NewRefreshingAuth(source), APIBase: srv.URL})
+	mu.Lock()
+	valid = ""
+	mu.Unlock()
+	for range 3 {
+		if err := c.Ping(); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	}
+	if n := source.n.Load(); n != 3 {
+		t.Errorf("tokens issued = %d, want 3", n)
+	}
+}
+
+func TestRefreshingAuth_MQTT(t *testing.T) {
+	b := lynxtest.NewTestBroker(t)
+	var mu sync.Mutex
+	var passwords []string
+	b.Authenticate = func(clientID, username, password string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		passwords = append(passwords, password)
+		return username == "bearer"
+	}
+
+	auth := lynx.NewRefreshingAuth(&tokenSource{})
+	connected := make(chan struct{}, 2)
+	opts := b.MqttOptions("test", func(mqtt.Client) { connected <- struct{}{} }, nil)
+	b.NewTestClient(t, "test", &lynx.Options{Authenticator: auth, MqttOptions: opts})
+	<-connected
+	b.DropConnections()
+	select {
+	case <-connected:
+	case <-time.After(time.Second * 10):
+		t.Fatal("not reconnected")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The tokens expire immediately, so every connect uses a new one
+	if len(passwords) != 2 || passwords[0] != "token-1" || passwords[1] != "token-2" {
+		t.Errorf("passwords = %v, want [token-1 token-2]", passwords)
+	}
+}
@@ -0,0 +1,94 @@
+package lynx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Topic is an MQTT topic in the Lynx layout, <client id>/<suffix>, for example 1234/obj/zwave/1/temperature.
+// Topic filters with the + and # wildcards use the same layout.
+type Topic string
+
+// NewTopic returns the topic for the levels under the client id, NewTopic(1234, "obj", "zwave") is 1234/obj/zwave.
+func NewTopic(clientID int64, levels ...string) Topic {
+	return Topic(strconv.FormatInt(clientID, 10) + "/" + strings.Join(levels, "/"))
+}
+
+// ObjTopic returns the topic for the levels under obj, ObjTopic(1234, "zwave", "1") is 1234/obj/zwave/1.
+func ObjTopic(clientID int64, levels ...string) Topic {
+	return NewTopic(clientID, append([]string{"obj"}, levels...)...)
+}
+
+// SetTopic returns the topic for the levels under set, SetTopic(1234, "zwave", "1") is 1234/set/zwave/1.
+func SetTopic(clientID int64, levels ...string) Topic {
+	return NewTopic(clientID, append([]string{"set"}, levels...)...)
+}
+
+// FunctionTopic returns the topic for the topic_<key> meta of the function, an empty key means "read".
+func FunctionTopic(clientID int64, fn *Function, key string) (Topic, error) {
+	topic, ok := fn.Topic(key)
+	if !ok {
+		if key == "" {
+			key = "read"
+		}
+		return "", fmt.Errorf("function %d has no topic_%s", fn.ID, key)
+	}
+	return NewTopic(clientID, topic), nil
+}
+
+// ParseTopic splits a topic in the Lynx layout into the client id and the suffix after it.
+func ParseTopic(topic string) (clientID int64, suffix string, err error) {
+	prefix, suffix, found := strings.Cut(topic, "/")
+	if !found || suffix == "" {
+		return 0, "", fmt.Errorf("invalid topic %q: missing client id prefix", topic)
+	}
+	clientID, err = strconv.ParseInt(prefix, 10, 64)
+	if err != nil || clientID <= 0 {
+		return 0, "", fmt.Errorf("invalid topic %q: invalid client id %q", topic, prefix)
+	}
+	return clientID, suffix, nil
+}
+
+// ClientID returns the client id prefix of the topic, or 0 if the topic is not in the Lynx layout.
+func (t Topic) ClientID() int64 {
+	clientID, _, _ := ParseTopic(string(t))
+	return clientID
+}
+
+// Suffix returns the topic without the client id prefix, or the whole topic if it is not in the Lynx layout.
+func (t Topic) Suffix() string {
+	_, suffix, err := ParseTopic(string(t))
+	if err != nil {
+		return string(t)
+	}
+	return suffix
+}
+
+// Match reports whether the topic matches the MQTT filter.
+func (t Topic) Match(filter string) bool {
+	return MatchTopic(filter, string(t))
+}
+
+func (t Topic) String() string {
+	return string(t)
+}
+
+// MatchTopic reports whether topic matches the MQTT filter, which may contain the + and # wildcards.
+// Topics starting with $ are only matched by filters starting with $.
+func MatchTopic(filter, topic string) bool {
+	if strings.HasPrefix(topic, "$") && !strings.HasPrefix(filter, "$") {
+		return false
+	}
+	f := strings.Split(filter, "/")
+	t := strings.Split(topic, "/")
+	for i, level := range f {
+		if level == "#" {
+			return true
+		}
+		if i >= len(t) || (level != "+" && level != t[i]) {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
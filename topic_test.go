@@ -0,0 +1,80 @@
+package lynx_test
+
+import (
+	"testing"
+
+	"github.com/IoTOpen/go-lynx"
+)
+
+func TestTopic(t *testing.T) {
+	if got := lynx.ObjTopic(1234, "zwave", "1", "temperature"); got != "1234/obj/zwave/1/temperature" {
+		t.Errorf("ObjTopic() = %s", got)
+	}
+	if got := lynx.SetTopic(1234, "zwave", "1"); got != "1234/set/zwave/1" {
+		t.Errorf("SetTopic() = %s", got)
+	}
+	if got := lynx.NewTopic(1234, "#"); got != "1234/#" {
+		t.Errorf("NewTopic() = %s", got)
+	}
+
+	fn := &lynx.Function{ID: 1, Meta: lynx.Meta{"topic_read": "obj/x", "topic_set": "set/obj/x"}}
+	if got, err := lynx.FunctionTopic(1234, fn, ""); err != nil || got != "1234/obj/x" {
+		t.Errorf("FunctionTopic(read) = %s, %v", got, err)
+	}
+	if got, err := lynx.FunctionTopic(1234, fn, "set"); err != nil || got != "1234/set/obj/x" {
+		t.Errorf("FunctionTopic(set) = %s, %v", got, err)
+	}
+	if _, err := lynx.FunctionTopic(1234, fn, "state"); err == nil {
+		t.Errorf("FunctionTopic(state) error = nil")
+	}
+
+	topic := lynx.Topic("1234/obj/zwave/1")
+	if topic.ClientID() != 1234 || topic.Suffix() != "obj/zwave/1" {
+		t.Errorf("ClientID(), Suffix() = %d, %s", topic.ClientID(), topic.Suffix())
+	}
+	if !topic.Match("1234/obj/+/1") || !topic.Match("+/obj/#") || topic.Match("1234/set/#") {
+		t.Errorf("Match() mismatch for %s", topic)
+	}
+}
+
+func TestParseTopic(t *testing.T) {
+	tests := []struct {
+		topic    string
+		clientID int64
+		suffix   string
+		wantErr  bool
+	}{
+		{"1234/obj/zwave/1", 1234, "obj/zwave/1", false},
+		{"1/x", 1, "x", false},
+		{"1234", 0, "", true},
+		{"1234/", 0, "", true},
+		{"abc/obj", 0, "", true},
+		{"0/obj", 0, "", true},
+		{"$SYS/broker", 0, "", true},
+	}
+	for _, tt := range tests {
+		clientID, suffix, err := lynx.ParseTopic(tt.topic)
+		if (err != nil) != tt.wantErr || clientID != tt.clientID || suffix != tt.suffix {
+			t.Errorf("ParseTopic(%q) = %d, %q, %v", tt.topic, clientID, suffix, err)
+		}
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"a/b", "a/b", true},
+		{"a/+", "a/b", true},
+		{"a/+", "a/b/c", false},
+		{"a/#", "a", true},
+		{"a/#", "a/b/c", true},
+		{"#", "$SYS/x", false},
+	}
+	for _, tt := range tests {
+		if got := lynx.MatchTopic(tt.filter, tt.topic); got != tt.want {
+			t.Errorf("MatchTopic(%q, %q) = %v, want %v", tt.filter, tt.topic, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
+	"iter"
+	"math"
 	"net/http"
 	"net/url"
 	"time"
@@ -19,6 +22,11 @@ type TraceEntry struct {
 	Description string          `json:"description"`
 }
 
+func (t *TraceEntry) Time() time.Time {
+	whole, fractals := math.Modf(t.Timestamp)
+	return time.Unix(int64(whole), int64(fractals*1000000000))
+}
+
 type TracePage struct {
 	Total    int64        `json:"total"`
 	LastTime float64      `json:"last"`
@@ -74,6 +82,10 @@ type TraceOptions struct {
 }
 
 func (c *Client) GetTraces(opts *TraceOptions) (*TracePage, error) {
+	return c.GetTracesContext(context.Background(), opts)
+}
+
+func (c *Client) GetTracesContext(ctx context.Context, opts *TraceOptions) (*TracePage, error) {
 	if opts == nil {
 		return nil, fmt.Errorf("options must be specified")
 	}
@@ -94,9 +106,94 @@ func (c *Client) GetTraces(opts *TraceOptions) (*TracePage, error) {
 	}
 
 	path := fmt.Sprintf("api/v2/trace?%s", query.Encode())
-	req := c.newRequest(http.MethodGet, path, nil)
+	req := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(req, res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
+
+// TracesIter returns an iterator over all trace entries in the window given by opts, starting at opts.Offset.
+// Pages of opts.Limit entries are fetched as the iterator advances until Total entries have been read.
+// Iteration stops after the first error.
+func (c *Client) TracesIter(ctx context.Context, opts *TraceOptions) iter.Seq2[TraceEntry, error] {
+	if opts == nil {
+		return func(yield func(TraceEntry, error) bool) {
+			yield(TraceEntry{}, fmt.Errorf("options must be specified"))
+		}
+	}
+	return paginate(ctx, opts.Offset, func(ctx context.Context, offset int64) ([]TraceEntry, int64, error) {
+		pageOpts := *opts
+		pageOpts.Offset = offset
+		page, err := c.GetTracesContext(ctx, &pageOpts)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Data, page.Total, nil
+	})
+}
+
+// TailTraces polls for new trace entries matching opts every interval and delivers them in ascending order
+// on the returned channel, which is closed when ctx is done.
+// Polling starts at opts.From, or now if it is zero, and opts.To, opts.Offset and opts.Order are ignored.
+// Errors are passed to onError, if set, and polling continues at the next interval.
+// An interval of zero or less polls every 10s.
+func (c *Client) TailTraces(ctx context.Context, opts *TraceOptions, interval time.Duration, onError func(error)) <-chan TraceEntry {
+	ch := make(chan TraceEntry)
+	if interval <= 0 {
+		interval = time.Second * 10
+	}
+	pollOpts := TraceOptions{}
+	if opts != nil {
+		pollOpts = *opts
+	}
+	if pollOpts.From.IsZero() {
+		pollOpts.From = time.Now()
+	}
+	if pollOpts.Limit <= 0 {
+		pollOpts.Limit = 100
+	}
+	pollOpts.Order = LogOrderAsc
+	pollOpts.Offset = 0
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		// The API has second resolution on from, so entries within the last second are
+		// returned again by the next poll and must be filtered out.
+		seen := make(map[string]bool)
+		from := pollOpts.From
+		for {
+			window := pollOpts
+			window.From = from
+			window.To = time.Now()
+			for entry, err := range c.TracesIter(ctx, &window) {
+				if err != nil {
+					if onError != nil && ctx.Err() == nil {
+						onError(err)
+					}
+					break
+				}
+				if seen[entry.ID] || entry.Time().Before(window.From) {
+					continue
+				}
+				if second := entry.Time().Truncate(time.Second); second.After(from) {
+					from = second
+					clear(seen)
+				}
+				seen[entry.ID] = true
+				select {
+				case ch <- entry:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,105 @@
+package lynx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IoTOpen/go-lynx"
+	"github.com/IoTOpen/go-lynx/lynxtest"
+)
+
+func TestClient_TracesIter(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	start := time.Now().Truncate(time.Second).Add(-time.Minute)
+	for i := range 7 {
+		srv.AddTrace(lynx.TraceEntry{ID: string(rune('a' + i)), Timestamp: float64(start.Unix() + int64(i))})
+	}
+	opts := &lynx.TraceOptions{Limit: 3, From: start, To: time.Now(), Order: lynx.LogOrderAsc}
+
+	var ids string
+	for entry, err := range c.TracesIter(t.Context(), opts) {
+		if err != nil {
+			t.Fatalf("TracesIter() error = %v", err)
+		}
+		ids += entry.ID
+	}
+	if ids != "abcdefg" || len(srv.Requests()) != 3 {
+		t.Errorf("TracesIter() = %q in %d requests, want abcdefg in 3", ids, len(srv.Requests()))
+	}
+
+	ids = ""
+	for entry, err := range c.TracesIter(t.Context(), opts) {
+		if err != nil {
+			t.Fatalf("TracesIter() error = %v", err)
+		}
+		ids += entry.ID
+		if entry.ID == "b" {
+			break
+		}
+	}
+	if ids != "ab" || len(srv.Requests()) != 4 {
+		t.Errorf("TracesIter() with break = %q in %d requests, want ab in 1", ids, len(srv.Requests())-3)
+	}
+
+	for _, err := range c.TracesIter(t.Context(), nil) {
+		if err == nil {
+			t.Errorf("TracesIter() without options error = nil")
+		}
+	}
+}
+
+func TestClient_TailTraces(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	// All entries are within the same second, which the API returns again on every poll
+	from := time.Now().Truncate(time.Second).Add(-time.Second)
+	at := func(ms int) float64 {
+		return float64(from.Add(time.Millisecond*time.Duration(ms)).UnixMicro()) / 1e6
+	}
+	srv.AddTrace(
+		lynx.TraceEntry{ID: "old", Timestamp: at(-500)},
+		lynx.TraceEntry{ID: "a", Timestamp: at(100)},
+		lynx.TraceEntry{ID: "b", Timestamp: at(200)},
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second*5)
+	defer cancel()
+	ch := c.TailTraces(ctx, &lynx.TraceOptions{From: from}, time.Millisecond*20, func(err error) {
+		t.Errorf("TailTraces() error = %v", err)
+	})
+	next := func() string {
+		select {
+		case entry := <-ch:
+			return entry.ID
+		case <-time.After(time.Millisecond * 200):
+			return ""
+		}
+	}
+	if a, b := next(), next(); a != "a" || b != "b" {
+		t.Fatalf("TailTraces() = %q, %q, want a, b", a, b)
+	}
+	srv.AddTrace(lynx.TraceEntry{ID: "c", Timestamp: at(300)})
+	if id := next(); id != "c" {
+		t.Errorf("TailTraces() = %q, want c", id)
+	}
+	if id := next(); id != "" {
+		t.Errorf("TailTraces() repeated entry %q", id)
+	}
+	cancel()
+	for range ch {
+	}
+}
+
+func TestClient_TailTracesInterval(t *testing.T) {
+	srv := lynxtest.NewServer()
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(t.Context())
+	ch := srv.Client().TailTraces(ctx, nil, 0, nil)
+	cancel()
+	for range ch {
+	}
+}
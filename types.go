@@ -8,15 +8,38 @@ import (
 )
 
 type (
+	// Error is returned when the API responds with an error status
 	Error struct {
 		Code    int    `json:"-"`
 		Message string `json:"message"`
+		// Fields holds validation errors per field, if reported by the API.
+		// It is a pointer to keep Error comparable.
+		Fields *FieldErrors `json:"fields,omitempty"`
+		// Method and Path of the failed request
+		Method string `json:"-"`
+		Path   string `json:"-"`
+		// RequestID is the request id reported by the server, if any
+		RequestID string `json:"-"`
+		// Body is a snippet of the raw response body when it could not be decoded
+		Body string `json:"-"`
 	}
-	Filter map[string]string
+	// FieldErrors are validation errors keyed by field name
+	FieldErrors map[string]string
+	Filter      map[string]string
 )
 
 func (e Error) Error() string {
-	return fmt.Sprintf("%s (%s - %d)", e.Message, http.StatusText(e.Code), e.Code)
+	msg := fmt.Sprintf("%s (%s - %d)", e.Message, http.StatusText(e.Code), e.Code)
+	if e.Method != "" {
+		msg += fmt.Sprintf(" from %s %s", e.Method, e.Path)
+	}
+	if e.RequestID != "" {
+		msg += ", request id " + e.RequestID
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
 }
 
 type Meta map[string]string
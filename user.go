@@ -1,6 +1,7 @@
 package lynx
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -30,8 +31,12 @@ type Address struct {
 }
 
 func (c *Client) Me() (*User, error) {
+	return c.MeContext(context.Background())
+}
+
+func (c *Client) MeContext(ctx context.Context) (*User, error) {
 	user := &User{}
-	request := c.newRequest(http.MethodGet, userMePath, nil)
+	request := c.newRequest(ctx, http.MethodGet, userMePath, nil)
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, user); err != nil {
 		return nil, err
@@ -40,8 +45,12 @@ func (c *Client) Me() (*User, error) {
 }
 
 func (c *Client) UpdateMe(u *User) (*User, error) {
+	return c.UpdateMeContext(context.Background(), u)
+}
+
+func (c *Client) UpdateMeContext(ctx context.Context, u *User) (*User, error) {
 	user := &User{}
-	request := c.newRequest(http.MethodPut, userMePath, requestBody(u))
+	request := c.newRequest(ctx, http.MethodPut, userMePath, requestBody(u))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, user); err != nil {
 		return nil, err
@@ -50,9 +59,13 @@ func (c *Client) UpdateMe(u *User) (*User, error) {
 }
 
 func (c *Client) UpdateUser(u *User) (*User, error) {
+	return c.UpdateUserContext(context.Background(), u)
+}
+
+func (c *Client) UpdateUserContext(ctx context.Context, u *User) (*User, error) {
 	user := &User{}
 	path := fmt.Sprintf("api/v2/user/%d", u.ID)
-	request := c.newRequest(http.MethodPut, path, requestBody(u))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(u))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, user); err != nil {
 		return nil, err
@@ -61,9 +74,13 @@ func (c *Client) UpdateUser(u *User) (*User, error) {
 }
 
 func (c *Client) GetUsers(filter Filter) ([]*User, error) {
+	return c.GetUsersContext(context.Background(), filter)
+}
+
+func (c *Client) GetUsersContext(ctx context.Context, filter Filter) ([]*User, error) {
 	res := make([]*User, 0, 5)
 	query := filter.ToURLValues()
-	req := c.newRequest(http.MethodGet, fmt.Sprintf("api/v2/user?%s", query.Encode()), nil)
+	req := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("api/v2/user?%s", query.Encode()), nil)
 	if err := c.do(req, &res); err != nil {
 		return nil, err
 	}
@@ -71,9 +88,13 @@ func (c *Client) GetUsers(filter Filter) ([]*User, error) {
 }
 
 func (c *Client) GetUserMeta(userID int64, key string) (*MetaObject, error) {
+	return c.GetUserMetaContext(context.Background(), userID, key)
+}
+
+func (c *Client) GetUserMetaContext(ctx context.Context, userID int64, key string) (*MetaObject, error) {
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/user/%d/meta/%s", userID, key)
-	request := c.newRequest(http.MethodGet, path, nil)
+	request := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err := c.do(request, mo); err != nil {
 		return nil, err
 	}
@@ -81,12 +102,16 @@ func (c *Client) GetUserMeta(userID int64, key string) (*MetaObject, error) {
 }
 
 func (c *Client) CreateUserMeta(userID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
+	return c.CreateUserMetaContext(context.Background(), userID, key, meta, silent)
+}
+
+func (c *Client) CreateUserMetaContext(ctx context.Context, userID int64, key string, meta MetaObject, silent bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/user/%d/meta/%s?%s", userID, key, query.Encode())
-	request := c.newRequest(http.MethodPost, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPost, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -95,13 +120,17 @@ func (c *Client) CreateUserMeta(userID int64, key string, meta MetaObject, silen
 }
 
 func (c *Client) UpdateUserMeta(userID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
+	return c.UpdateUserMetaContext(context.Background(), userID, key, meta, silent, createMissing)
+}
+
+func (c *Client) UpdateUserMetaContext(ctx context.Context, userID int64, key string, meta MetaObject, silent, createMissing bool) (*MetaObject, error) {
 	query := url.Values{
 		"silent":         []string{fmt.Sprintf("%t", silent)},
 		"create_missing": []string{fmt.Sprintf("%t", createMissing)},
 	}
 	mo := &MetaObject{}
 	path := fmt.Sprintf("api/v2/user/%d/meta/%s?%s", userID, key, query.Encode())
-	request := c.newRequest(http.MethodPut, path, requestBody(meta))
+	request := c.newRequest(ctx, http.MethodPut, path, requestBody(meta))
 	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
 	if err := c.do(request, mo); err != nil {
 		return nil, err
@@ -110,11 +139,15 @@ func (c *Client) UpdateUserMeta(userID int64, key string, meta MetaObject, silen
 }
 
 func (c *Client) DeleteUserMeta(userID int64, key string, silent bool) error {
+	return c.DeleteUserMetaContext(context.Background(), userID, key, silent)
+}
+
+func (c *Client) DeleteUserMetaContext(ctx context.Context, userID int64, key string, silent bool) error {
 	query := url.Values{
 		"silent": []string{fmt.Sprintf("%t", silent)},
 	}
 	path := fmt.Sprintf("api/v2/user/%d/meta/%s?%s", userID, key, query.Encode())
-	request := c.newRequest(http.MethodDelete, path, nil)
+	request := c.newRequest(ctx, http.MethodDelete, path, nil)
 	if err := c.do(request, nil); err != nil {
 		return err
 	}